@@ -0,0 +1,134 @@
+package apperrors
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Kind classifies a domain error, the HTTP layer responds with the status matching the kind.
+type Kind string
+
+// Kinds of domain errors.
+const (
+	KindNotFound      Kind = "not_found"
+	KindConflict      Kind = "conflict"
+	KindValidation    Kind = "validation"
+	KindUnauthorized  Kind = "unauthorized"
+	KindForbidden     Kind = "forbidden"
+	KindLimitExceeded Kind = "limit_exceeded"
+)
+
+// uniqueViolation is the Postgres error code of a unique constraint violation
+const uniqueViolation = "23505"
+
+// Error is a domain error with a stable code clients can rely on, the message is safe to show to users.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the underlying cause so checks like errors.Is(err, sql.ErrNoRows) keep working.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches errors of the same kind and code, so a wrapped copy still matches its sentinel.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Kind == e.Kind && t.Code == e.Code
+}
+
+// NotFound returns an error for a missing resource.
+func NotFound(code, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+// Conflict returns an error for a request conflicting with the current state of a resource.
+func Conflict(code, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+// Validation returns an error for invalid input.
+func Validation(code, message string) *Error {
+	return &Error{Kind: KindValidation, Code: code, Message: message}
+}
+
+// Unauthorized returns an error for missing or invalid credentials.
+func Unauthorized(code, message string) *Error {
+	return &Error{Kind: KindUnauthorized, Code: code, Message: message}
+}
+
+// Forbidden returns an error for an action the user isn't allowed to take.
+func Forbidden(code, message string) *Error {
+	return &Error{Kind: KindForbidden, Code: code, Message: message}
+}
+
+// LimitExceeded returns an error for a request over a quota or rate limit.
+func LimitExceeded(code, message string) *Error {
+	return &Error{Kind: KindLimitExceeded, Code: code, Message: message}
+}
+
+// IsKind reports whether the error or any error it wraps is a domain error of the kind.
+func IsKind(err error, kind Kind) bool {
+	var appErr *Error
+	return errors.As(err, &appErr) && appErr.Kind == kind
+}
+
+// FromDB translates a database error of the entity into a domain error, sql.ErrNoRows becomes NotFound
+// and a unique violation becomes Conflict, other errors are returned as is.
+func FromDB(err error, entity string) error {
+	if err == nil {
+		return nil
+	}
+
+	code := strings.ReplaceAll(entity, " ", "_")
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return &Error{
+			Kind:    KindNotFound,
+			Code:    code + "_not_found",
+			Message: entity + " not found",
+			Err:     err,
+		}
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		// Name the column for the default <table>_<column>_key constraints, the constraint itself is never exposed
+		message := entity + " already exists"
+		if column, ok := uniqueColumn(pqErr); ok {
+			message = fmt.Sprintf("%s with this %s already exists", entity, strings.ReplaceAll(column, "_", " "))
+		}
+
+		return &Error{
+			Kind:    KindConflict,
+			Code:    code + "_already_exists",
+			Message: message,
+			Err:     err,
+		}
+	}
+
+	return err
+}
+
+// uniqueColumn returns the column of a unique violation on a default named single column constraint
+func uniqueColumn(pqErr *pq.Error) (string, bool) {
+	prefix := pqErr.Table + "_"
+	if pqErr.Table == "" || !strings.HasPrefix(pqErr.Constraint, prefix) || !strings.HasSuffix(pqErr.Constraint, "_key") {
+		return "", false
+	}
+
+	column := strings.TrimSuffix(strings.TrimPrefix(pqErr.Constraint, prefix), "_key")
+
+	return column, column != ""
+}
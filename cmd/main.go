@@ -1,12 +1,20 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/aslam-ep/go-e-commerce/config"
 	"github.com/aslam-ep/go-e-commerce/database"
 	"github.com/aslam-ep/go-e-commerce/router"
+	"github.com/aslam-ep/go-e-commerce/utils"
 )
 
 func main() {
@@ -22,12 +30,102 @@ func main() {
 	defer db.Close()
 	log.Println("Connected to database.")
 
-	router := router.NewRouter(db)
+	// Load the keys for signing access tokens
+	keys, err := loadKeySet()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	router, err := router.NewRouter(db, keys)
+	if err != nil {
+		log.Fatal(err)
+	}
 	router.SetupRoutes()
 
-	// Start the server
-	log.Println("Starting the server on :", config.AppConfig.ServerPort)
-	if err := http.ListenAndServe(":"+config.AppConfig.ServerPort, router.Mux); err != nil {
+	// Stop on SIGINT or SIGTERM, a second signal kills the process right away
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Erase the data of deleted users in the background
+	jobCtx, cancelJobs := context.WithCancel(context.Background())
+	jobDone := make(chan struct{})
+	go func() {
+		defer close(jobDone)
+		router.ErasureJob.Run(jobCtx)
+	}()
+
+	server := &http.Server{
+		Addr:              ":" + config.AppConfig.ServerPort,
+		Handler:           router.Mux,
+		ReadTimeout:       time.Duration(config.AppConfig.ServerReadTimeout) * time.Second,
+		ReadHeaderTimeout: time.Duration(config.AppConfig.ServerReadHeaderTimeout) * time.Second,
+		WriteTimeout:      time.Duration(config.AppConfig.ServerWriteTimeout) * time.Second,
+		IdleTimeout:       time.Duration(config.AppConfig.ServerIdleTimeout) * time.Second,
+	}
+
+	// Start the server, it is ready once listening
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
 		log.Fatalf("Could not start the server: :%v\n", err)
 	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Println("Starting the server on :", config.AppConfig.ServerPort)
+		if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+	router.Readiness.SetReady(true)
+
+	select {
+	case err := <-serverErr:
+		log.Fatalf("Server stopped unexpectedly: %v\n", err)
+	case <-ctx.Done():
+	}
+	stop()
+
+	// Stop receiving new traffic before draining the in-flight requests, the drain delay leaves the load balancer
+	// one readiness probe period to see the server as not ready
+	log.Println("Shutting down the server...")
+	router.Readiness.SetReady(false)
+	time.Sleep(time.Duration(config.AppConfig.ShutdownDrainDelay) * time.Second)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Duration(config.AppConfig.ShutdownTimeout)*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Println("Could not drain the connections in time:", err)
+		server.Close()
+	}
+
+	cancelJobs()
+	<-jobDone
+	log.Println("Server stopped.")
+}
+
+// minJWTSecretLength is the shortest shared secret accepted for signing HS256 tokens
+const minJWTSecretLength = 32
+
+// loadKeySet loads the asymmetric signing keys when configured, falling back to an explicitly set shared JWT secret
+func loadKeySet() (*utils.KeySet, error) {
+	if config.AppConfig.JWTSigningKeyFile == "" {
+		if len(config.AppConfig.JWTSecret) < minJWTSecretLength {
+			return nil, fmt.Errorf("no JWT signing key configured, set JWT_SIGNING_KEY_FILE or a JWT_SECRET of at least %d characters", minJWTSecretLength)
+		}
+
+		log.Println("No JWT signing key file configured, signing tokens with the shared secret and publishing no JWKS.")
+		return utils.NewHMACKeySet(config.AppConfig.JWTSecret), nil
+	}
+
+	keys, err := utils.LoadKeySet(
+		config.AppConfig.JWTSigningKeyID,
+		config.AppConfig.JWTSigningKeyFile,
+		config.AppConfig.JWTVerificationKeys,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load JWT keys: %v", err)
+	}
+	log.Println("Loaded JWT signing key", config.AppConfig.JWTSigningKeyID)
+
+	return keys, nil
 }
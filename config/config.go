@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -20,6 +21,50 @@ type Config struct {
 	DBTimeout    int
 	JWTSecret    string
 	APIRateLimit int
+
+	ServerReadTimeout       int
+	ServerReadHeaderTimeout int
+	ServerWriteTimeout      int
+	ServerIdleTimeout       int
+	ShutdownDrainDelay      int
+	ShutdownTimeout         int
+
+	TokenRevocationStore string
+
+	JWTSigningKeyID     string
+	JWTSigningKeyFile   string
+	JWTVerificationKeys string
+
+	AppEnv         string
+	AppURL         string
+	Mailer         string
+	MailerFilePath string
+
+	AllowUnverifiedLogin    bool
+	AllowUnverifiedCheckout bool
+
+	LoginLockoutThreshold int
+	LoginLockoutMinutes   int
+	LoginIPMaxFailures    int
+
+	MFAIssuer string
+
+	ErasureGraceDays          int
+	ErasureJobIntervalMinutes int
+
+	ProblemTypeBaseURL string
+
+	OIDCProviders []OIDCProviderConfig
+}
+
+// OIDCProviderConfig struct to hold the client settings of an OpenID Connect provider
+type OIDCProviderConfig struct {
+	Name         string
+	Type         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
 }
 
 // AppConfig variable to hold the server config values
@@ -41,8 +86,42 @@ func LoadConfig() {
 		DBPassword:   getEnv("DB_PASSWORD", "password"),
 		DBName:       getEnv("DB_NAME", "e-commerce"),
 		DBTimeout:    getEnvAsInt("DB_TIMEOUT", 2),
-		JWTSecret:    getEnv("JWT_SECRET", "someSecretKey"),
+		JWTSecret:    getEnv("JWT_SECRET", ""),
 		APIRateLimit: getEnvAsInt("API_RATE_LIMIT", 100),
+
+		ServerReadTimeout:       getEnvAsInt("SERVER_READ_TIMEOUT", 15),
+		ServerReadHeaderTimeout: getEnvAsInt("SERVER_READ_HEADER_TIMEOUT", 5),
+		ServerWriteTimeout:      getEnvAsInt("SERVER_WRITE_TIMEOUT", 30),
+		ServerIdleTimeout:       getEnvAsInt("SERVER_IDLE_TIMEOUT", 120),
+		ShutdownDrainDelay:      getEnvAsInt("SHUTDOWN_DRAIN_DELAY", 5),
+		ShutdownTimeout:         getEnvAsInt("SHUTDOWN_TIMEOUT", 30),
+
+		TokenRevocationStore: getEnv("TOKEN_REVOCATION_STORE", "postgres"),
+
+		JWTSigningKeyID:     getEnv("JWT_SIGNING_KEY_ID", ""),
+		JWTSigningKeyFile:   getEnv("JWT_SIGNING_KEY_FILE", ""),
+		JWTVerificationKeys: getEnv("JWT_VERIFICATION_KEYS", ""),
+
+		AppEnv:         getEnv("APP_ENV", "production"),
+		AppURL:         getEnv("APP_URL", "http://localhost:3000"),
+		Mailer:         getEnv("MAILER", ""),
+		MailerFilePath: getEnv("MAILER_FILE_PATH", "mails.log"),
+
+		AllowUnverifiedLogin:    getEnvAsBool("ALLOW_UNVERIFIED_LOGIN", true),
+		AllowUnverifiedCheckout: getEnvAsBool("ALLOW_UNVERIFIED_CHECKOUT", false),
+
+		LoginLockoutThreshold: getEnvAsInt("LOGIN_LOCKOUT_THRESHOLD", 5),
+		LoginLockoutMinutes:   getEnvAsInt("LOGIN_LOCKOUT_MINUTES", 15),
+		LoginIPMaxFailures:    getEnvAsInt("LOGIN_IP_MAX_FAILURES", 20),
+
+		MFAIssuer: getEnv("MFA_ISSUER", "go-e-commerce"),
+
+		ErasureGraceDays:          getEnvAsInt("ERASURE_GRACE_DAYS", 30),
+		ErasureJobIntervalMinutes: getEnvAsInt("ERASURE_JOB_INTERVAL_MINUTES", 60),
+
+		ProblemTypeBaseURL: getEnv("PROBLEM_TYPE_BASE_URL", ""),
+
+		OIDCProviders: getOIDCProviders(),
 	}
 }
 
@@ -62,3 +141,37 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+// getEnvAsBool reads environment variable as boolean and return default value if not found
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.ParseBool(valueStr); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getOIDCProviders reads the providers listed in OIDC_PROVIDERS, each configured by OIDC_<NAME>_* variables, the
+// type is oidc for OpenID Connect providers or github
+func getOIDCProviders() []OIDCProviderConfig {
+	var providers []OIDCProviderConfig
+
+	for _, name := range strings.Split(getEnv("OIDC_PROVIDERS", ""), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		providers = append(providers, OIDCProviderConfig{
+			Name:         name,
+			Type:         getEnv(prefix+"TYPE", "oidc"),
+			Issuer:       getEnv(prefix+"ISSUER", ""),
+			ClientID:     getEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+			RedirectURL:  getEnv(prefix+"REDIRECT_URL", ""),
+		})
+	}
+
+	return providers
+}
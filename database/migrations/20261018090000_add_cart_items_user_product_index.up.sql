@@ -0,0 +1,16 @@
+-- Merge duplicate cart rows of a product into the oldest row before enforcing one row per product
+UPDATE "cart_items" AS "c"
+SET "quantity" = "d"."quantity", "updated_at" = CURRENT_TIMESTAMP
+FROM (
+  SELECT MIN("id") AS "id", SUM("quantity") AS "quantity"
+  FROM "cart_items"
+  GROUP BY "user_id", "product_id"
+  HAVING COUNT(*) > 1
+) AS "d"
+WHERE "c"."id" = "d"."id";
+
+DELETE FROM "cart_items" AS "c"
+USING "cart_items" AS "k"
+WHERE "c"."user_id" = "k"."user_id" AND "c"."product_id" = "k"."product_id" AND "c"."id" > "k"."id";
+
+CREATE UNIQUE INDEX "idx_cart_items_user_product" ON "cart_items" ("user_id", "product_id");
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "order_status_history";
+
+ALTER TABLE "orderes" DROP CONSTRAINT IF EXISTS "chk_orderes_status";
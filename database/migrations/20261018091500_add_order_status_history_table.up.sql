@@ -0,0 +1,23 @@
+ALTER TABLE "orderes" ADD CONSTRAINT "chk_orderes_status"
+  CHECK ("status" IN ('pending', 'paid', 'packed', 'shipped', 'delivered', 'cancelled', 'refunded'));
+
+CREATE TABLE "order_status_history" (
+  "id" SERIAL PRIMARY KEY,
+  "order_id" INTEGER NOT NULL,
+  "from_status" VARCHAR(100),
+  "to_status" VARCHAR(100) NOT NULL,
+  "actor_id" INTEGER,
+  "note" VARCHAR(255),
+  "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  CONSTRAINT "fk_order_id"
+    FOREIGN KEY ("order_id")
+    REFERENCES "orderes" ("id")
+    ON DELETE CASCADE,
+  CONSTRAINT "fk_actor_id"
+    FOREIGN KEY ("actor_id")
+    REFERENCES "users" ("id")
+    ON DELETE SET NULL
+);
+
+CREATE INDEX "idx_order_status_history_order_id" ON "order_status_history" ("order_id");
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS "idx_products_vendor_id";
+DROP INDEX IF EXISTS "idx_order_items_product_id";
+DROP INDEX IF EXISTS "idx_order_items_order_id";
+
+ALTER TABLE "order_items"
+  DROP CONSTRAINT IF EXISTS "chk_order_items_fulfilment_status",
+  DROP COLUMN IF EXISTS "shipped_at",
+  DROP COLUMN IF EXISTS "packed_at",
+  DROP COLUMN IF EXISTS "tracking_number",
+  DROP COLUMN IF EXISTS "fulfilment_status";
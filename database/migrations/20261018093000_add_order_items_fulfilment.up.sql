@@ -0,0 +1,11 @@
+ALTER TABLE "order_items"
+  ADD COLUMN "fulfilment_status" VARCHAR(100) NOT NULL DEFAULT 'pending',
+  ADD COLUMN "tracking_number" VARCHAR(100),
+  ADD COLUMN "packed_at" TIMESTAMP WITH TIME ZONE,
+  ADD COLUMN "shipped_at" TIMESTAMP WITH TIME ZONE,
+  ADD CONSTRAINT "chk_order_items_fulfilment_status"
+    CHECK ("fulfilment_status" IN ('pending', 'packed', 'shipped'));
+
+CREATE INDEX "idx_order_items_order_id" ON "order_items" ("order_id");
+CREATE INDEX "idx_order_items_product_id" ON "order_items" ("product_id");
+CREATE INDEX "idx_products_vendor_id" ON "products" ("vendor_id");
@@ -0,0 +1,11 @@
+ALTER TABLE "refresh_tokens"
+  ADD COLUMN "family_id" VARCHAR(64),
+  ADD COLUMN "revoked_at" TIMESTAMP WITH TIME ZONE,
+  ADD COLUMN "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+-- Existing tokens each start their own family
+UPDATE "refresh_tokens" SET "family_id" = 'legacy-' || "id";
+
+ALTER TABLE "refresh_tokens" ALTER COLUMN "family_id" SET NOT NULL;
+
+CREATE INDEX "idx_refresh_tokens_family_id" ON "refresh_tokens" ("family_id");
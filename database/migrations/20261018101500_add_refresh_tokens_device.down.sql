@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "idx_refresh_tokens_user_id";
+
+ALTER TABLE "refresh_tokens"
+  DROP COLUMN IF EXISTS "ip_address",
+  DROP COLUMN IF EXISTS "user_agent";
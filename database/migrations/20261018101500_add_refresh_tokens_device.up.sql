@@ -0,0 +1,5 @@
+ALTER TABLE "refresh_tokens"
+  ADD COLUMN "user_agent" VARCHAR(255),
+  ADD COLUMN "ip_address" VARCHAR(64);
+
+CREATE INDEX "idx_refresh_tokens_user_id" ON "refresh_tokens" ("user_id");
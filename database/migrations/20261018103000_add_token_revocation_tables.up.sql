@@ -0,0 +1,16 @@
+CREATE TABLE "revoked_tokens" (
+  "jti" VARCHAR(64) PRIMARY KEY,
+  "expires_at" TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX "idx_revoked_tokens_expires_at" ON "revoked_tokens" ("expires_at");
+
+CREATE TABLE "user_token_revocations" (
+  "user_id" INTEGER PRIMARY KEY,
+  "revoked_before" TIMESTAMP WITH TIME ZONE NOT NULL,
+
+  CONSTRAINT "fk_user_id"
+    FOREIGN KEY ("user_id")
+    REFERENCES "users" ("id")
+    ON DELETE CASCADE
+);
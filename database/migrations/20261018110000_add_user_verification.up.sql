@@ -0,0 +1,27 @@
+ALTER TABLE "users"
+  ADD COLUMN "email_verified_at" TIMESTAMP WITH TIME ZONE,
+  ADD COLUMN "phone_verified_at" TIMESTAMP WITH TIME ZONE;
+
+-- Accounts registered before the verification existed are treated as verified so they can keep checking out
+UPDATE "users" SET "email_verified_at" = "created_at";
+
+CREATE TABLE "verification_codes" (
+    "id" SERIAL PRIMARY KEY,
+    "user_id" INT NOT NULL,
+    "channel" VARCHAR(20) NOT NULL,
+    "target" VARCHAR(255) NOT NULL,
+    "code_hash" VARCHAR(64) NOT NULL,
+    "attempts" INT NOT NULL DEFAULT 0,
+    "expires_at" TIMESTAMP WITH TIME ZONE NOT NULL,
+    "consumed_at" TIMESTAMP WITH TIME ZONE,
+    "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "fk_user_id"
+    FOREIGN KEY ("user_id")
+    REFERENCES "users" ("id")
+    ON DELETE CASCADE,
+
+    CONSTRAINT "chk_verification_codes_channel" CHECK ("channel" IN ('email', 'phone'))
+);
+
+CREATE INDEX "idx_verification_codes_user_channel" ON "verification_codes" ("user_id", "channel", "created_at");
@@ -0,0 +1,21 @@
+ALTER TABLE "users"
+  ADD COLUMN "failed_login_attempts" INT NOT NULL DEFAULT 0,
+  ADD COLUMN "locked_until" TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE "login_attempts" (
+    "id" SERIAL PRIMARY KEY,
+    "user_id" INT,
+    "email" VARCHAR(255) NOT NULL,
+    "ip_address" VARCHAR(64) NOT NULL,
+    "user_agent" VARCHAR(255),
+    "reason" VARCHAR(50) NOT NULL,
+    "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "fk_user_id"
+    FOREIGN KEY ("user_id")
+    REFERENCES "users" ("id")
+    ON DELETE SET NULL
+);
+
+CREATE INDEX "idx_login_attempts_ip_address" ON "login_attempts" ("ip_address", "created_at");
+CREATE INDEX "idx_login_attempts_user_id" ON "login_attempts" ("user_id", "created_at");
@@ -0,0 +1,27 @@
+CREATE TABLE "user_mfa" (
+  "user_id" INTEGER PRIMARY KEY,
+  "secret" VARCHAR(64) NOT NULL,
+  "enabled_at" TIMESTAMP WITH TIME ZONE,
+  "last_used_step" BIGINT NOT NULL DEFAULT 0,
+  "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  CONSTRAINT "fk_user_id"
+    FOREIGN KEY ("user_id")
+    REFERENCES "users" ("id")
+    ON DELETE CASCADE
+);
+
+CREATE TABLE "mfa_recovery_codes" (
+  "id" SERIAL PRIMARY KEY,
+  "user_id" INTEGER NOT NULL,
+  "code_hash" VARCHAR(64) NOT NULL,
+  "used_at" TIMESTAMP WITH TIME ZONE,
+  "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  CONSTRAINT "fk_user_id"
+    FOREIGN KEY ("user_id")
+    REFERENCES "users" ("id")
+    ON DELETE CASCADE
+);
+
+CREATE INDEX "idx_mfa_recovery_codes_user_id" ON "mfa_recovery_codes" ("user_id");
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS "oauth_states";
+DROP TABLE IF EXISTS "user_identities";
+
+-- Fails while social login accounts without a phone exist
+ALTER TABLE "users" ALTER COLUMN "phone" SET NOT NULL;
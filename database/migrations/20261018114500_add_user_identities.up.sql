@@ -0,0 +1,28 @@
+-- Accounts created by a social login have no phone until the user adds one
+ALTER TABLE "users" ALTER COLUMN "phone" DROP NOT NULL;
+
+CREATE TABLE "user_identities" (
+  "id" SERIAL PRIMARY KEY,
+  "user_id" INTEGER NOT NULL,
+  "provider" VARCHAR(50) NOT NULL,
+  "subject" VARCHAR(255) NOT NULL,
+  "email" VARCHAR(255),
+  "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  CONSTRAINT "fk_user_id"
+    FOREIGN KEY ("user_id")
+    REFERENCES "users" ("id")
+    ON DELETE CASCADE,
+
+  CONSTRAINT "uq_user_identities_provider_subject" UNIQUE ("provider", "subject")
+);
+
+CREATE INDEX "idx_user_identities_user_id" ON "user_identities" ("user_id");
+
+CREATE TABLE "oauth_states" (
+  "state" VARCHAR(64) PRIMARY KEY,
+  "provider" VARCHAR(50) NOT NULL,
+  "code_verifier" VARCHAR(128) NOT NULL,
+  "nonce" VARCHAR(64) NOT NULL,
+  "expires_at" TIMESTAMP WITH TIME ZONE NOT NULL
+);
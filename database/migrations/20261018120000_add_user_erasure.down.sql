@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "idx_users_pending_erasure";
+
+ALTER TABLE "users"
+  DROP COLUMN IF EXISTS "erased_at",
+  DROP COLUMN IF EXISTS "deleted_at";
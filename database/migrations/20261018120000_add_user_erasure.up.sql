@@ -0,0 +1,8 @@
+ALTER TABLE "users"
+  ADD COLUMN "deleted_at" TIMESTAMP WITH TIME ZONE,
+  ADD COLUMN "erased_at" TIMESTAMP WITH TIME ZONE;
+
+-- Users deleted before the column existed start their grace period from the last update
+UPDATE "users" SET "deleted_at" = "updated_at" WHERE "is_deleted" = true;
+
+CREATE INDEX "idx_users_pending_erasure" ON "users" ("deleted_at") WHERE "is_deleted" = true AND "erased_at" IS NULL;
@@ -0,0 +1,16 @@
+CREATE TABLE "email_change_tokens" (
+    "id" SERIAL PRIMARY KEY,
+    "user_id" INT NOT NULL,
+    "new_email" VARCHAR(255) NOT NULL,
+    "token_hash" VARCHAR(64) UNIQUE NOT NULL,
+    "expires_at" TIMESTAMP WITH TIME ZONE NOT NULL,
+    "used_at" TIMESTAMP WITH TIME ZONE,
+    "created_at" TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT "fk_user_id"
+    FOREIGN KEY ("user_id")
+    REFERENCES "users" ("id")
+    ON DELETE CASCADE
+);
+
+CREATE INDEX "idx_email_change_tokens_user_id" ON "email_change_tokens" ("user_id");
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS "idx_addresses_user_id_created_at" ON "addresses" ("user_id", "created_at", "id");
@@ -0,0 +1,10 @@
+CREATE TABLE "oauth_login_codes" (
+  "code_hash" VARCHAR(64) PRIMARY KEY,
+  "user_id" INTEGER NOT NULL,
+  "expires_at" TIMESTAMP WITH TIME ZONE NOT NULL,
+
+  CONSTRAINT "fk_user_id"
+    FOREIGN KEY ("user_id")
+    REFERENCES "users" ("id")
+    ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "idx_users_created_at";
+DROP INDEX IF EXISTS "idx_orderes_user_id_created_at";
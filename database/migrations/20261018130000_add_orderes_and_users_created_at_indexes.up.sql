@@ -0,0 +1,2 @@
+CREATE INDEX IF NOT EXISTS "idx_orderes_user_id_created_at" ON "orderes" ("user_id", "created_at", "id");
+CREATE INDEX IF NOT EXISTS "idx_users_created_at" ON "users" ("created_at", "id");
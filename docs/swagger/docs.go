@@ -15,55 +15,34 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
-            "post": {
-                "description": "Login a user, on success get the refreshToken and accessToken",
-                "consumes": [
-                    "application/json"
-                ],
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "Public keys for verifying access tokens, looked up by the kid header of the token",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
-                ],
-                "summary": "Login user",
-                "parameters": [
-                    {
-                        "description": "Login request",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/auth.LoginReq"
-                        }
-                    }
+                    "Auth"
                 ],
+                "summary": "JSON web key set",
                 "responses": {
                     "200": {
-                        "description": "Login response",
-                        "schema": {
-                            "$ref": "#/definitions/auth.LoginRes"
-                        }
-                    },
-                    "400": {
-                        "description": "Default response",
-                        "schema": {
-                            "$ref": "#/definitions/utils.MessageRes"
-                        }
-                    },
-                    "401": {
-                        "description": "Default response",
+                        "description": "Key set response",
                         "schema": {
-                            "$ref": "#/definitions/utils.MessageRes"
+                            "$ref": "#/definitions/utils.JWKS"
                         }
                     }
                 }
             }
         },
-        "/auth/refresh-token": {
-            "post": {
-                "description": "Refresh token, send the new access token based on refresh token",
+        "/admin/orders/{order_id}/status": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move an order to a new status when the transition is allowed, recording the admin as the actor",
                 "consumes": [
                     "application/json"
                 ],
@@ -71,75 +50,60 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "Admin"
                 ],
-                "summary": "Refresh token",
+                "summary": "Update order status",
                 "parameters": [
                     {
-                        "description": "Refresh token request",
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "order_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Order status update request",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/auth.RefreshTokenReq"
+                            "$ref": "#/definitions/order.UpdateStatusReq"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Refresh token response",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/auth.RefreshTokenRes"
+                            "$ref": "#/definitions/order.Order"
                         }
                     },
                     "400": {
-                        "description": "Default response",
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/utils.MessageRes"
                         }
                     },
                     "401": {
-                        "description": "Default response",
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/utils.MessageRes"
                         }
-                    }
-                }
-            }
-        },
-        "/users/register": {
-            "post": {
-                "description": "Register a new user with the provided details",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "user"
-                ],
-                "summary": "Register a new user",
-                "parameters": [
-                    {
-                        "description": "User registration request",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/user.CreateUserReq"
+                            "$ref": "#/definitions/utils.MessageRes"
                         }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/user.UserRes"
+                            "$ref": "#/definitions/utils.MessageRes"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/utils.MessageRes"
                         }
@@ -147,9 +111,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}": {
-            "post": {
-                "description": "Get User Details by provided ID in url",
+        "/admin/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Search users by name, email or phone with role and deleted filters page by page, pass the next_cursor of a page to get the next one, admin only",
                 "consumes": [
                     "application/json"
                 ],
@@ -157,23 +126,62 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "Admin"
                 ],
-                "summary": "Get User Details",
+                "summary": "List users",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Name, email or phone contains",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "user",
+                            "vendor",
+                            "admin"
+                        ],
+                        "type": "string",
+                        "description": "User role",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exclude",
+                            "include",
+                            "only"
+                        ],
+                        "type": "string",
+                        "description": "Soft deleted users, excluded by default",
+                        "name": "deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to get",
+                        "name": "cursor",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Users per page, max 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort, one of -created_at, created_at, -id, id, newest first by default",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/user.UserRes"
+                            "$ref": "#/definitions/user.ListUserRes"
                         }
                     },
                     "400": {
@@ -181,18 +189,36 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/utils.MessageRes"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
                     }
                 }
             }
         },
-        "/users/{id}/addresses/": {
+        "/admin/users/{user_id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all addresses for the authenticated user",
+                "description": "Get the user details including soft deleted and locked users, admin only",
                 "consumes": [
                     "application/json"
                 ],
@@ -200,14 +226,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Address"
+                    "Admin"
                 ],
-                "summary": "Get all addresses",
+                "summary": "Get user",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "User ID",
-                        "name": "id",
+                        "name": "user_id",
                         "in": "path",
                         "required": true
                     }
@@ -216,7 +242,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/address.AddressRes"
+                            "$ref": "#/definitions/user.User"
                         }
                     },
                     "400": {
@@ -225,6 +251,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.MessageRes"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -234,14 +272,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}/addresses/create": {
-            "post": {
+        "/admin/users/{user_id}/lock": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Adding a new address for the authenticated user",
+                "description": "Lock the user out of login for the given minutes and end all of their sessions, admin only",
                 "consumes": [
                     "application/json"
                 ],
@@ -249,35 +287,60 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Address"
+                    "Admin"
                 ],
-                "summary": "Adding a new address",
+                "summary": "Lock user",
                 "parameters": [
                     {
-                        "description": "Address request for create and update",
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Lock duration",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/address.AddressReq"
+                            "$ref": "#/definitions/auth.LockUserReq"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Default response",
                         "schema": {
-                            "$ref": "#/definitions/address.Address"
+                            "$ref": "#/definitions/utils.MessageRes"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "403": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "404": {
+                        "description": "Default response",
                         "schema": {
                             "$ref": "#/definitions/utils.MessageRes"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Default response",
                         "schema": {
                             "$ref": "#/definitions/utils.MessageRes"
                         }
@@ -285,14 +348,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}/addresses/{adddress_id}": {
-            "get": {
+        "/admin/users/{user_id}/logout": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a specific address by ID for the authenticated user",
+                "description": "End every session of the user and revoke their access tokens, admin only",
                 "consumes": [
                     "application/json"
                 ],
@@ -300,40 +363,51 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Address"
+                    "Admin"
                 ],
-                "summary": "Get address by ID",
+                "summary": "Force logout user",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Address ID",
-                        "name": "address_id",
+                        "name": "user_id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Default response",
                         "schema": {
-                            "$ref": "#/definitions/address.Address"
+                            "$ref": "#/definitions/utils.MessageRes"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "403": {
+                        "description": "Default response",
                         "schema": {
                             "$ref": "#/definitions/utils.MessageRes"
                         }
                     },
                     "404": {
-                        "description": "Not Found",
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "500": {
+                        "description": "Default response",
                         "schema": {
                             "$ref": "#/definitions/utils.MessageRes"
                         }
@@ -341,14 +415,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}/addresses/{address_id}/delete": {
-            "delete": {
+        "/admin/users/{user_id}/restore": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a specific address by ID for the authenticated user",
+                "description": "Restore a soft deleted user, admin only",
                 "consumes": [
                     "application/json"
                 ],
@@ -356,21 +430,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Address"
+                    "Admin"
                 ],
-                "summary": "Delete address",
+                "summary": "Restore user",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Address ID",
-                        "name": "address_id",
+                        "name": "user_id",
                         "in": "path",
                         "required": true
                     }
@@ -388,23 +455,41 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.MessageRes"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/utils.MessageRes"
                         }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
                     }
                 }
             }
         },
-        "/users/{id}/addresses/{address_id}/set-default": {
+        "/admin/users/{user_id}/role": {
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Set a specific address as the default for the authenticated user",
+                "description": "Change the role of a user and revoke their access tokens, admins can't change their own role",
                 "consumes": [
                     "application/json"
                 ],
@@ -412,23 +497,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Address"
+                    "Admin"
                 ],
-                "summary": "Set default address",
+                "summary": "Change user role",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "User ID",
-                        "name": "id",
+                        "name": "user_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "Address ID",
-                        "name": "address_id",
-                        "in": "path",
-                        "required": true
+                        "description": "New role",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.ChangeRoleReq"
+                        }
                     }
                 ],
                 "responses": {
@@ -444,7 +531,19 @@ const docTemplate = `{
                             "$ref": "#/definitions/utils.MessageRes"
                         }
                     },
-                    "404": {
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "404": {
                         "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/utils.MessageRes"
@@ -453,14 +552,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}/addresses/{address_id}/update": {
+        "/admin/users/{user_id}/unlock": {
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Updated address by ID for the current user",
+                "description": "Clear the failed logins and the lockout of the user, admin only",
                 "consumes": [
                     "application/json"
                 ],
@@ -468,49 +567,114 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Address"
+                    "Admin"
                 ],
-                "summary": "Updated address by ID",
+                "summary": "Unlock user",
                 "parameters": [
                     {
                         "type": "integer",
                         "description": "User ID",
-                        "name": "id",
+                        "name": "user_id",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
                     },
-                    {
-                        "type": "integer",
-                        "description": "Address ID",
-                        "name": "address_id",
-                        "in": "path",
-                        "required": true
+                    "400": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "403": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "404": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
                     },
+                    "500": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/email/change": {
+            "post": {
+                "security": [
                     {
-                        "description": "Address request for create and update",
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mail a confirmation link to the new email and a notice to the current one, the email changes once confirmed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Change email",
+                "parameters": [
+                    {
+                        "description": "Change email request",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/address.AddressReq"
+                            "$ref": "#/definitions/auth.ChangeEmailReq"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Default response",
                         "schema": {
-                            "$ref": "#/definitions/address.Address"
+                            "$ref": "#/definitions/utils.MessageRes"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "409": {
+                        "description": "Default response",
                         "schema": {
                             "$ref": "#/definitions/utils.MessageRes"
                         }
                     },
                     "500": {
-                        "description": "Internal Server Error",
+                        "description": "Default response",
                         "schema": {
                             "$ref": "#/definitions/utils.MessageRes"
                         }
@@ -518,9 +682,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}/delete": {
-            "delete": {
-                "description": "Delete User Details by provided ID in url",
+        "/auth/email/confirm": {
+            "post": {
+                "description": "Confirm the new email with the token from the confirmation email, all sessions of the user are revoked",
                 "consumes": [
                     "application/json"
                 ],
@@ -528,27 +692,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "Auth"
                 ],
-                "summary": "Delete User Details",
+                "summary": "Confirm email change",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Confirm email change request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.ConfirmEmailChangeReq"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Default response",
                         "schema": {
                             "$ref": "#/definitions/utils.MessageRes"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "409": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "500": {
+                        "description": "Default response",
                         "schema": {
                             "$ref": "#/definitions/utils.MessageRes"
                         }
@@ -556,9 +734,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}/password-reset": {
-            "put": {
-                "description": "Reset User Password by provided ID in url and password in body",
+        "/auth/forgot-password": {
+            "post": {
+                "description": "Mail a single use password reset link to the email, the response is the same for unregistered and throttled emails",
                 "consumes": [
                     "application/json"
                 ],
@@ -566,36 +744,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "Auth"
                 ],
-                "summary": "Reset User Password",
+                "summary": "Forgot password",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Password change request",
+                        "description": "Forgot password request",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/user.ResetPasswordReq"
+                            "$ref": "#/definitions/auth.ForgotPasswordReq"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Default response",
                         "schema": {
                             "$ref": "#/definitions/utils.MessageRes"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "429": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "500": {
+                        "description": "Default response",
                         "schema": {
                             "$ref": "#/definitions/utils.MessageRes"
                         }
@@ -603,9 +786,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/{id}/update": {
-            "put": {
-                "description": "Update User Details by provided ID in url and details in body",
+        "/auth/login": {
+            "post": {
+                "description": "Login a user, on success get the refreshToken and accessToken, or an mfaToken to verify when two factor authentication is enabled",
                 "consumes": [
                     "application/json"
                 ],
@@ -613,223 +796,3745 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "Auth"
                 ],
-                "summary": "Update User Details",
+                "summary": "Login user",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Login request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.LoginReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login response",
+                        "schema": {
+                            "$ref": "#/definitions/auth.LoginRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
                     },
+                    "403": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "429": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "500": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "description": "Logout by revoking the session of the given refresh token and the bearer access token when present",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Logout",
+                "parameters": [
                     {
-                        "description": "User Update request",
+                        "description": "Logout request",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/user.UpdateUserReq"
+                            "$ref": "#/definitions/auth.LogoutReq"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Default response",
                         "schema": {
-                            "$ref": "#/definitions/user.UserRes"
+                            "$ref": "#/definitions/utils.MessageRes"
                         }
                     },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Default response",
                         "schema": {
                             "$ref": "#/definitions/utils.MessageRes"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "address.Address": {
-            "type": "object",
-            "properties": {
-                "address_line_1": {
-                    "type": "string"
-                },
-                "address_line_2": {
-                    "type": "string"
-                },
-                "city": {
-                    "type": "string"
-                },
-                "country": {
-                    "type": "string"
-                },
-                "created_at": {
+        },
+        "/auth/logout-all": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Logout the authenticated user from all devices by revoking every session",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Logout from all devices",
+                "responses": {
+                    "200": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "500": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/mfa/confirm": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Enable two factor authentication with a code from the authenticator app, the recovery codes are shown only once",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Confirm MFA",
+                "parameters": [
+                    {
+                        "description": "MFA code request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.MFACodeReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Recovery codes response",
+                        "schema": {
+                            "$ref": "#/definitions/auth.MFARecoveryCodesRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "409": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "429": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "500": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/mfa/disable": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Disable two factor authentication with an authenticator or recovery code",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Disable MFA",
+                "parameters": [
+                    {
+                        "description": "MFA code request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.MFACodeReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "429": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "500": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/mfa/enroll": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Generate a TOTP secret and otpauth URI for the authenticator app, enabled once confirmed with a code",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Enroll MFA",
+                "responses": {
+                    "200": {
+                        "description": "MFA enroll response",
+                        "schema": {
+                            "$ref": "#/definitions/auth.MFAEnrollRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "409": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "500": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/mfa/verify": {
+            "post": {
+                "description": "Complete the login with the mfaToken and an authenticator or recovery code, on success get the refreshToken and accessToken",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Verify MFA login",
+                "parameters": [
+                    {
+                        "description": "Verify MFA request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.VerifyMFAReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login response",
+                        "schema": {
+                            "$ref": "#/definitions/auth.LoginRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "429": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "500": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/exchange": {
+            "post": {
+                "description": "Exchange the single use code of a completed social login for the refreshToken and accessToken, or an mfaToken to verify when two factor authentication is enabled",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Social login token exchange",
+                "parameters": [
+                    {
+                        "description": "Social login exchange request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.OIDCExchangeReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login response",
+                        "schema": {
+                            "$ref": "#/definitions/auth.LoginRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "403": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "429": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "500": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/oidc/{provider}/callback": {
+            "get": {
+                "description": "Complete the social login with the authorization response, an account is linked when both the provider and the account verified the email, or created on first login.\nRedirects to the frontend with a single use code in the URL fragment to exchange for the tokens, or the error code on failure",
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Social login callback",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider name",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authorization code",
+                        "name": "code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Login state",
+                        "name": "state",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Found"
+                    }
+                }
+            }
+        },
+        "/auth/oidc/{provider}/login": {
+            "get": {
+                "description": "Redirect to the login provider for signing in using the authorization code flow with PKCE, the login state is kept in a cookie of the browser",
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Social login",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider name",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Found"
+                    },
+                    "404": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "500": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh-token": {
+            "post": {
+                "description": "Refresh token, rotates the refresh token and sends the new token pair, reusing a rotated token revokes its family",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Refresh token",
+                "parameters": [
+                    {
+                        "description": "Refresh token request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.RefreshTokenReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Refresh token response",
+                        "schema": {
+                            "$ref": "#/definitions/auth.RefreshTokenRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Register a new user with the provided details",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Register a new user",
+                "parameters": [
+                    {
+                        "description": "User registration request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.RegisterUserReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/reset-password": {
+            "post": {
+                "description": "Set a new password with the token from the reset email, all sessions of the user are revoked",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Reset password",
+                "parameters": [
+                    {
+                        "description": "Reset password request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.ResetPasswordReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "500": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the active sessions of the authenticated user with device details and issue time",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "List sessions",
+                "responses": {
+                    "200": {
+                        "description": "Sessions response",
+                        "schema": {
+                            "$ref": "#/definitions/auth.ListSessionRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "500": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/verify/confirm": {
+            "post": {
+                "description": "Verify the email or phone of the account with the code sent to it, a code allows a limited number of attempts",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Confirm verification",
+                "parameters": [
+                    {
+                        "description": "Confirm verification request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.ConfirmVerificationReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "429": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "500": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/verify/resend": {
+            "post": {
+                "description": "Send a new verification code to the email or phone of the account, codes can be resent once a minute",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Resend verification code",
+                "parameters": [
+                    {
+                        "description": "Resend verification request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/auth.ResendVerificationReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "429": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "500": {
+                        "description": "Default response",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Report the process is alive without checking any dependency",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/health.LivenessRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/": {
+            "get": {
+                "description": "Get all products in the catalog, optionally filtered by vendor",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Get all products",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Vendor ID",
+                        "name": "vendor_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/product.ListProductRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/create": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adding a new product for the authenticated vendor",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Adding a new product",
+                "parameters": [
+                    {
+                        "description": "Product request for create and update",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/product.CreateUpdateProductReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/product.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{product_id}": {
+            "get": {
+                "description": "Get a specific product from the catalog by ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Get product by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/product.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{product_id}/delete": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Soft delete a product owned by the authenticated vendor",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Delete product",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{product_id}/update": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a product owned by the authenticated vendor",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Update product by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Product request for create and update",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/product.CreateUpdateProductReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/product.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Check the database connection and schema migrations, reporting every check along with the connection pool",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/health.ReadinessRes"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/health.ReadinessRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}": {
+            "post": {
+                "description": "Get User Details by provided ID in url",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get User Details",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/addresses/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the addresses of the authenticated user page by page, pass the next_cursor of a page to get the next one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Address"
+                ],
+                "summary": "Get all addresses",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to get",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Addresses per page, max 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort, one of id, -id, created_at, -created_at",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/address.ListAddressRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/addresses/create": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Adding a new address for the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Address"
+                ],
+                "summary": "Adding a new address",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Address request for create and update",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/address.CreateUpdateAddressRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/address.Address"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/addresses/{address_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a specific address by ID for the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Address"
+                ],
+                "summary": "Get address by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Address ID",
+                        "name": "address_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/address.Address"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/addresses/{address_id}/delete": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a specific address by ID for the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Address"
+                ],
+                "summary": "Delete address",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Address ID",
+                        "name": "address_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/addresses/{address_id}/set-default": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set a specific address as the default for the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Address"
+                ],
+                "summary": "Set default address",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Address ID",
+                        "name": "address_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/addresses/{address_id}/update": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Updated address by ID for the current user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Address"
+                ],
+                "summary": "Updated address by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Address ID",
+                        "name": "address_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Address request for create and update",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/address.CreateUpdateAddressRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/address.Address"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/cart/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the cart items of the authenticated user with line totals and subtotal",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Get cart",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/cart.CartRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/cart/add": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a product to the authenticated user's cart, increasing the quantity when already present",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Add item to cart",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Add cart item request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cart.AddCartItemReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/cart.CartItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/cart/clear": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove all the items from the authenticated user's cart",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Clear cart",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/cart/{item_id}/delete": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove an item from the authenticated user's cart",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Remove cart item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cart item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/cart/{item_id}/update": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change the quantity of a cart item of the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Change cart item quantity",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Cart item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update cart item request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/cart.UpdateCartItemReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/cart.CartItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/delete": {
+            "delete": {
+                "description": "Delete User Details by provided ID in url",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Delete User Details",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download the profile, addresses, orders and sessions of the user as a JSON archive",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Export user data",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/privacy.ExportRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/orders/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the authenticated user's orders matching the filters page by page, pass the next_cursor of a page to get the next one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "List orders",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Placed on or after date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Placed on or before date (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to get",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Orders per page, max 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort, one of -created_at, created_at, -id, id, newest first by default",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/order.ListOrderRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/orders/checkout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Place an order from the authenticated user's cart to the given address",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Checkout cart",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Checkout request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/order.CheckoutReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/order.Order"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/order.CheckoutErrorRes"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/orders/{order_id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a specific order of the authenticated user with items, shipping address, totals and status timeline",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Get order by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "order_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/order.OrderDetailRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/orders/{order_id}/cancel": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancel an order of the authenticated user which hasn't been packed yet",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Cancel order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "order_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/order.Order"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/password-reset": {
+            "put": {
+                "description": "Reset User Password by provided ID in url and password in body",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Reset User Password",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Password change request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.ResetPasswordReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/update": {
+            "put": {
+                "description": "Update User Details by provided ID in url and details in body",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Update User Details",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User Update request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.UpdateUserReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/vendor/order-items/": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the order items referencing the authenticated vendor's products page by page, pass the next_cursor of a page to get the next one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vendor"
+                ],
+                "summary": "List vendor order items",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Fulfilment status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the page to get",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page, max 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort by the order time, one of -created_at, created_at, -id, id, newest first by default",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/order.ListVendorOrderItemRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/vendor/order-items/{item_id}/pack": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mark an order item of the authenticated vendor's product as packed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vendor"
+                ],
+                "summary": "Pack order item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/order.FulfilItemRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/vendor/order-items/{item_id}/ship": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mark an order item of the authenticated vendor's product as shipped with the tracking number",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vendor"
+                ],
+                "summary": "Ship order item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Ship order item request",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/order.ShipItemReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/order.FulfilItemRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/vendor/orders/{order_id}/fulfilment": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the fulfilment progress per vendor of an order containing the authenticated vendor's products",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vendor"
+                ],
+                "summary": "Get order fulfilment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "order_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/order.OrderFulfilment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/utils.MessageRes"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "address.Address": {
+            "type": "object",
+            "properties": {
+                "address_line_1": {
+                    "type": "string"
+                },
+                "address_line_2": {
+                    "type": "string"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_default": {
+                    "type": "boolean"
+                },
+                "postal_code": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "address.CreateUpdateAddressRequest": {
+            "type": "object",
+            "required": [
+                "address_line_1",
+                "city",
+                "country",
+                "postal_code",
+                "state"
+            ],
+            "properties": {
+                "address_line_1": {
+                    "type": "string",
+                    "maxLength": 250,
+                    "minLength": 5
+                },
+                "address_line_2": {
+                    "type": "string",
+                    "maxLength": 250
+                },
+                "city": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3
+                },
+                "country": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "postal_code": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3
+                },
+                "state": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "address.ListAddressRes": {
+            "type": "object",
+            "properties": {
+                "addresses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/address.Address"
+                    }
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "has_more": {
+                    "type": "boolean"
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.ChangeEmailReq": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_email"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string"
+                },
+                "new_email": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.ConfirmEmailChangeReq": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.ConfirmVerificationReq": {
+            "type": "object",
+            "required": [
+                "channel",
+                "code",
+                "email"
+            ],
+            "properties": {
+                "channel": {
+                    "type": "string",
+                    "enum": [
+                        "email",
+                        "phone"
+                    ]
+                },
+                "code": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.ForgotPasswordReq": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.ListSessionRes": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/auth.Session"
+                    }
+                }
+            }
+        },
+        "auth.LockUserReq": {
+            "type": "object",
+            "required": [
+                "minutes"
+            ],
+            "properties": {
+                "minutes": {
+                    "type": "integer",
+                    "maximum": 525600,
+                    "minimum": 1
+                }
+            }
+        },
+        "auth.LoginReq": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.LoginRes": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "mfa_required": {
+                    "type": "boolean"
+                },
+                "mfa_token": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.LogoutReq": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.MFACodeReq": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 20,
+                    "minLength": 6
+                }
+            }
+        },
+        "auth.MFAEnrollRes": {
+            "type": "object",
+            "properties": {
+                "otpauth_uri": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.MFARecoveryCodesRes": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "auth.OIDCExchangeReq": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.RefreshTokenReq": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.RefreshTokenRes": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.RegisterUserReq": {
+            "type": "object",
+            "required": [
+                "email",
+                "name",
+                "password",
+                "phone",
+                "role"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "user",
+                        "vendor"
+                    ]
+                }
+            }
+        },
+        "auth.ResendVerificationReq": {
+            "type": "object",
+            "required": [
+                "channel",
+                "email"
+            ],
+            "properties": {
+                "channel": {
+                    "type": "string",
+                    "enum": [
+                        "email",
+                        "phone"
+                    ]
+                },
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.ResetPasswordReq": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "token"
+            ],
+            "properties": {
+                "new_password": {
+                    "type": "string",
+                    "minLength": 6
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.Session": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "issued_at": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "auth.VerifyMFAReq": {
+            "type": "object",
+            "required": [
+                "code",
+                "mfa_token"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 20,
+                    "minLength": 6
+                },
+                "mfa_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "cart.AddCartItemReq": {
+            "type": "object",
+            "required": [
+                "product_id",
+                "quantity"
+            ],
+            "properties": {
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "maximum": 100
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "cart.CartItem": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "cart.CartItemRes": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "line_total": {
+                    "type": "number"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "cart.CartRes": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/cart.CartItemRes"
+                    }
+                },
+                "sub_total": {
+                    "type": "number"
+                }
+            }
+        },
+        "cart.UpdateCartItemReq": {
+            "type": "object",
+            "required": [
+                "quantity"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "maximum": 100
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "health.Check": {
+            "type": "object",
+            "properties": {
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "migration": {
+                    "$ref": "#/definitions/health.MigrationStatus"
+                },
+                "pool": {
+                    "$ref": "#/definitions/health.PoolStats"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "health.LivenessRes": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "health.MigrationStatus": {
+            "type": "object",
+            "properties": {
+                "dirty": {
+                    "type": "boolean"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "health.PoolStats": {
+            "type": "object",
+            "properties": {
+                "idle": {
+                    "type": "integer"
+                },
+                "in_use": {
+                    "type": "integer"
+                },
+                "max_open_connections": {
+                    "type": "integer"
+                },
+                "open_connections": {
+                    "type": "integer"
+                },
+                "wait_count": {
+                    "type": "integer"
+                },
+                "wait_duration": {
+                    "type": "string"
+                }
+            }
+        },
+        "health.ReadinessRes": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/health.Check"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "order.CheckoutErrorRes": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "requested": {
+                    "type": "integer"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "order.CheckoutReq": {
+            "type": "object",
+            "required": [
+                "address_id",
+                "payment_method"
+            ],
+            "properties": {
+                "address_id": {
+                    "type": "integer"
+                },
+                "payment_method": {
+                    "type": "string",
+                    "enum": [
+                        "cod",
+                        "card",
+                        "upi"
+                    ]
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "order.FulfilItemRes": {
+            "type": "object",
+            "properties": {
+                "fulfilment": {
+                    "$ref": "#/definitions/order.OrderFulfilment"
+                },
+                "item": {
+                    "$ref": "#/definitions/order.VendorOrderItem"
+                }
+            }
+        },
+        "order.ListOrderRes": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "has_more": {
+                    "type": "boolean"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/order.Order"
+                    }
+                }
+            }
+        },
+        "order.ListVendorOrderItemRes": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "has_more": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/order.VendorOrderItem"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "order.Order": {
+            "type": "object",
+            "properties": {
+                "address_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/order.OrderItem"
+                    }
+                },
+                "payment_method": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total_amount": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "order.OrderDetailRes": {
+            "type": "object",
+            "properties": {
+                "address_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "fulfilment": {
+                    "$ref": "#/definitions/order.OrderFulfilment"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/order.OrderItem"
+                    }
+                },
+                "payment_method": {
+                    "type": "string"
+                },
+                "shipping_address": {
+                    "$ref": "#/definitions/address.Address"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "sub_total": {
+                    "type": "number"
+                },
+                "timeline": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/order.StatusHistory"
+                    }
+                },
+                "total_amount": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "order.OrderFulfilment": {
+            "type": "object",
+            "properties": {
+                "order_id": {
+                    "type": "integer"
+                },
+                "order_status": {
+                    "type": "string"
+                },
+                "packed_items": {
+                    "type": "integer"
+                },
+                "shipped_items": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total_items": {
+                    "type": "integer"
+                },
+                "vendors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/order.VendorFulfilment"
+                    }
+                }
+            }
+        },
+        "order.OrderItem": {
+            "type": "object",
+            "properties": {
+                "fulfilment_status": {
                     "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "is_default": {
-                    "type": "boolean"
+                "line_total": {
+                    "type": "number"
                 },
-                "postal_code": {
-                    "type": "string"
+                "order_id": {
+                    "type": "integer"
                 },
-                "state": {
-                    "type": "string"
+                "price_at_order": {
+                    "type": "number"
                 },
-                "updated_at": {
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
                     "type": "string"
                 },
-                "user_id": {
+                "quantity": {
                     "type": "integer"
+                },
+                "tracking_number": {
+                    "type": "string"
                 }
             }
         },
-        "address.AddressReq": {
+        "order.ShipItemReq": {
             "type": "object",
             "required": [
-                "address_line_1",
-                "city",
-                "country",
-                "postal_code",
-                "state"
+                "tracking_number"
             ],
             "properties": {
-                "address_line_1": {
-                    "type": "string",
-                    "maxLength": 250,
-                    "minLength": 5
-                },
-                "address_line_2": {
+                "tracking_number": {
                     "type": "string",
-                    "maxLength": 250
+                    "maxLength": 100
+                }
+            }
+        },
+        "order.StatusHistory": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "type": "integer"
                 },
-                "city": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 3
+                "created_at": {
+                    "type": "string"
                 },
-                "country": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 3
+                "from_status": {
+                    "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "postal_code": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 3
+                "note": {
+                    "type": "string"
                 },
-                "state": {
+                "order_id": {
+                    "type": "integer"
+                },
+                "to_status": {
+                    "type": "string"
+                }
+            }
+        },
+        "order.UpdateStatusReq": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "actor_id": {
+                    "type": "integer"
+                },
+                "note": {
                     "type": "string",
-                    "maxLength": 100,
-                    "minLength": 3
+                    "maxLength": 255
                 },
-                "user_id": {
+                "order_id": {
                     "type": "integer"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "paid",
+                        "packed",
+                        "shipped",
+                        "delivered",
+                        "cancelled",
+                        "refunded"
+                    ]
                 }
             }
         },
-        "address.AddressRes": {
+        "order.VendorFulfilment": {
             "type": "object",
             "properties": {
-                "addresses": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/address.Address"
-                    }
+                "packed_items": {
+                    "type": "integer"
                 },
-                "count": {
+                "shipped_items": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total_items": {
+                    "type": "integer"
+                },
+                "vendor_id": {
                     "type": "integer"
                 }
             }
         },
-        "auth.LoginReq": {
+        "order.VendorOrderItem": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
+                "fulfilment_status": {
                     "type": "string"
                 },
-                "password": {
+                "id": {
+                    "type": "integer"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "order_status": {
+                    "type": "string"
+                },
+                "ordered_at": {
+                    "type": "string"
+                },
+                "packed_at": {
+                    "type": "string"
+                },
+                "price_at_order": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "shipped_at": {
+                    "type": "string"
+                },
+                "tracking_number": {
                     "type": "string"
                 }
             }
         },
-        "auth.LoginRes": {
+        "privacy.ExportRes": {
             "type": "object",
             "properties": {
-                "access_token": {
-                    "type": "string"
+                "addresses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/address.Address"
+                    }
                 },
-                "refresh_token": {
+                "exported_at": {
                     "type": "string"
+                },
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/order.Order"
+                    }
+                },
+                "profile": {
+                    "$ref": "#/definitions/user.User"
+                },
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/auth.Session"
+                    }
                 }
             }
         },
-        "auth.RefreshTokenReq": {
+        "product.CreateUpdateProductReq": {
             "type": "object",
             "required": [
-                "refresh_token"
+                "name"
             ],
             "properties": {
-                "refresh_token": {
-                    "type": "string"
+                "description": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3
+                },
+                "price": {
+                    "type": "number"
+                },
+                "stock_count": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "vendor_id": {
+                    "type": "integer"
                 }
             }
         },
-        "auth.RefreshTokenRes": {
+        "product.ListProductRes": {
             "type": "object",
             "properties": {
-                "access_token": {
-                    "type": "string"
+                "count": {
+                    "type": "integer"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/product.Product"
+                    }
                 }
             }
         },
-        "user.CreateUserReq": {
+        "product.Product": {
             "type": "object",
-            "required": [
-                "email",
-                "name",
-                "password",
-                "phone",
-                "role"
-            ],
             "properties": {
-                "email": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
                     "type": "string"
                 },
+                "id": {
+                    "type": "integer"
+                },
                 "name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 3
+                    "type": "string"
                 },
-                "password": {
-                    "type": "string",
-                    "minLength": 6
+                "price": {
+                    "type": "number"
                 },
-                "phone": {
+                "stock_count": {
+                    "type": "integer"
+                },
+                "updated_at": {
                     "type": "string"
                 },
+                "vendor_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "user.ChangeRoleReq": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
                 "role": {
                     "type": "string",
                     "enum": [
                         "user",
-                        "vendor"
+                        "vendor",
+                        "admin"
                     ]
                 }
             }
         },
+        "user.ListUserRes": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "has_more": {
+                    "type": "boolean"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/user.User"
+                    }
+                }
+            }
+        },
         "user.ResetPasswordReq": {
             "type": "object",
             "required": [
@@ -855,8 +4560,7 @@ const docTemplate = `{
             "required": [
                 "id",
                 "name",
-                "phone",
-                "role"
+                "phone"
             ],
             "properties": {
                 "id": {
@@ -879,35 +4583,99 @@ const docTemplate = `{
                 }
             }
         },
-        "user.UserRes": {
+        "user.User": {
             "type": "object",
             "properties": {
                 "created_at": {
                     "type": "string"
                 },
+                "deleted_at": {
+                    "type": "string"
+                },
                 "email": {
                     "type": "string"
                 },
+                "email_verified_at": {
+                    "type": "string"
+                },
+                "erased_at": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
+                "is_deleted": {
+                    "type": "boolean"
+                },
+                "locked_until": {
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
                 },
+                "password": {
+                    "type": "string"
+                },
                 "phone": {
                     "type": "string"
                 },
+                "phone_verified_at": {
+                    "type": "string"
+                },
                 "role": {
                     "type": "string"
                 },
-                "udapted_at": {
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "utils.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string"
+                },
+                "crv": {
+                    "type": "string"
+                },
+                "e": {
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "kty": {
                     "type": "string"
+                },
+                "n": {
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "string"
+                }
+            }
+        },
+        "utils.JWKS": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/utils.JWK"
+                    }
                 }
             }
         },
         "utils.MessageRes": {
             "type": "object",
             "properties": {
+                "code": {
+                    "type": "string"
+                },
                 "message": {
                     "type": "string"
                 },
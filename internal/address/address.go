@@ -29,8 +29,10 @@ type CreateUpdateAddressRequest struct {
 	Country      string `json:"country" validate:"required,min=3,max=100"`
 }
 
-// ListAddressRes struct for returning set of addresses
+// ListAddressRes struct for returning a page of addresses
 type ListAddressRes struct {
-	Count     int        `json:"count"`
-	Addresses *[]Address `json:"addresses"`
+	Count      int        `json:"count"`
+	Addresses  *[]Address `json:"addresses"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+	HasMore    bool       `json:"has_more"`
 }
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/aslam-ep/go-e-commerce/internal/pagination"
 	"github.com/aslam-ep/go-e-commerce/utils"
 	"github.com/go-chi/chi/v5"
 )
@@ -22,7 +23,7 @@ func NewHandler(service Service) *Handler {
 }
 
 func (h *Handler) getIDsFromParam(r *http.Request) (int, int, error) {
-	userIDStr := chi.URLParam(r, "id")
+	userIDStr := chi.URLParam(r, "user_id")
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
 		return -1, -1, err
@@ -44,37 +45,37 @@ func (h *Handler) getIDsFromParam(r *http.Request) (int, int, error) {
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
+// @Param        user_id  path    int                         true  "User ID"
 // @Param        body  body      CreateUpdateAddressRequest  true  "Address request for create and update"
 // @Success      200   {object}  Address
 // @Failure      400   {object}  utils.MessageRes
 // @Failure      401   {object}  utils.MessageRes
 // @Failure      500   {object}  utils.MessageRes
-// @Router       /users/{id}/addresses/create [post]
+// @Router       /users/{user_id}/addresses/create [post]
 func (h *Handler) CreateAddress(w http.ResponseWriter, r *http.Request) {
 	var addressReq CreateUpdateAddressRequest
 	if err := utils.ReadFromRequest(r, &addressReq); err != nil {
-		utils.WriterErrorResponse(w, http.StatusBadRequest, err.Error())
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	userIDStr := chi.URLParam(r, "id")
+	userIDStr := chi.URLParam(r, "user_id")
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
 		log.Println("id: ", err)
-		utils.WriterErrorResponse(w, http.StatusBadRequest, err.Error())
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 	addressReq.UserID = int64(userID)
 
 	if err := utils.Validate.Struct(addressReq); err != nil {
-		log.Println("validation: ", err)
-		utils.WriterErrorResponse(w, http.StatusBadRequest, err.Error())
+		utils.WriteValidationError(w, r, err)
 		return
 	}
 
 	res, err := h.service.CreateAddress(r.Context(), &addressReq)
 	if err != nil {
-		utils.WriterErrorResponse(w, http.StatusInternalServerError, err.Error())
+		utils.WriteError(w, r, err)
 		return
 	}
 
@@ -83,28 +84,37 @@ func (h *Handler) CreateAddress(w http.ResponseWriter, r *http.Request) {
 
 // GetAllAddress godoc
 // @Summary      Get all addresses
-// @Description  Get all addresses for the authenticated user
+// @Description  Get the addresses of the authenticated user page by page, pass the next_cursor of a page to get the next one
 // @Tags         Address
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Success      200  {object}  AddressRes
-// @Param        id             path   int  true  "User ID"
-// @Failure      400  {object}  utils.MessageRes
-// @Failure      401   {object}  utils.MessageRes
-// @Failure      404  {object}  utils.MessageRes
-// @Router       /users/{id}/addresses/ [get]
+// @Param        user_id  path      int     true   "User ID"
+// @Param        cursor   query     string  false  "Cursor of the page to get"
+// @Param        limit    query     int     false  "Addresses per page, max 100"
+// @Param        sort     query     string  false  "Sort, one of id, -id, created_at, -created_at"
+// @Success      200      {object}  ListAddressRes
+// @Failure      400      {object}  utils.MessageRes
+// @Failure      401      {object}  utils.MessageRes
+// @Failure      500      {object}  utils.MessageRes
+// @Router       /users/{user_id}/addresses/ [get]
 func (h *Handler) GetAllAddress(w http.ResponseWriter, r *http.Request) {
-	userIDStr := chi.URLParam(r, "id")
+	userIDStr := chi.URLParam(r, "user_id")
 	userID, err := strconv.Atoi(userIDStr)
 	if err != nil {
-		utils.WriterErrorResponse(w, http.StatusBadRequest, err.Error())
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params, err := pagination.ParamsFromRequest(r)
+	if err != nil {
+		utils.WriteError(w, r, err)
 		return
 	}
 
-	res, err := h.service.GetAllAddress(r.Context(), userID)
+	res, err := h.service.GetAllAddress(r.Context(), userID, params)
 	if err != nil {
-		utils.WriterErrorResponse(w, http.StatusNotFound, err.Error())
+		utils.WriteError(w, r, err)
 		return
 	}
 
@@ -118,23 +128,23 @@ func (h *Handler) GetAllAddress(w http.ResponseWriter, r *http.Request) {
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id             path   int  true  "User ID"
+// @Param        user_id        path   int  true  "User ID"
 // @Param        address_id     path   int  true  "Address ID"
 // @Success      200  {object}  Address
 // @Failure      400  {object}  utils.MessageRes
 // @Failure      401  {object}  utils.MessageRes
 // @Failure      404  {object}  utils.MessageRes
-// @Router       /users/{id}/addresses/{adddress_id} [get]
+// @Router       /users/{user_id}/addresses/{address_id} [get]
 func (h *Handler) GetAddressByID(w http.ResponseWriter, r *http.Request) {
 	addressID, userID, err := h.getIDsFromParam(r)
 	if err != nil {
-		utils.WriterErrorResponse(w, http.StatusBadRequest, err.Error())
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	res, err := h.service.GetAddressByID(r.Context(), addressID, userID)
 	if err != nil {
-		utils.WriterErrorResponse(w, http.StatusNotFound, err.Error())
+		utils.WriteError(w, r, err)
 		return
 	}
 
@@ -148,24 +158,24 @@ func (h *Handler) GetAddressByID(w http.ResponseWriter, r *http.Request) {
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id             path   int  true  "User ID"
+// @Param        user_id        path   int  true  "User ID"
 // @Param        address_id     path   int  true  "Address ID"
 // @Param        body  body CreateUpdateAddressRequest true  "Address request for create and update"
 // @Success      200  {object}  Address
 // @Failure      400  {object}  utils.MessageRes
 // @Failure      401  {object}  utils.MessageRes
 // @Failure      500  {object}  utils.MessageRes
-// @Router       /users/{id}/addresses/{address_id}/update [put]
+// @Router       /users/{user_id}/addresses/{address_id}/update [put]
 func (h *Handler) UpdateAddress(w http.ResponseWriter, r *http.Request) {
 	var addressReq CreateUpdateAddressRequest
 	if err := utils.ReadFromRequest(r, &addressReq); err != nil {
-		utils.WriterErrorResponse(w, http.StatusBadRequest, err.Error())
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	addressID, userID, err := h.getIDsFromParam(r)
 	if err != nil {
-		utils.WriterErrorResponse(w, http.StatusBadRequest, err.Error())
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -173,13 +183,13 @@ func (h *Handler) UpdateAddress(w http.ResponseWriter, r *http.Request) {
 	addressReq.UserID = int64(userID)
 
 	if err := utils.Validate.Struct(addressReq); err != nil {
-		utils.WriterErrorResponse(w, http.StatusBadRequest, err.Error())
+		utils.WriteValidationError(w, r, err)
 		return
 	}
 
 	res, err := h.service.UpdateAddress(r.Context(), &addressReq)
 	if err != nil {
-		utils.WriterErrorResponse(w, http.StatusInternalServerError, err.Error())
+		utils.WriteError(w, r, err)
 		return
 	}
 
@@ -193,23 +203,23 @@ func (h *Handler) UpdateAddress(w http.ResponseWriter, r *http.Request) {
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id             path   int  true  "User ID"
+// @Param        user_id        path   int  true  "User ID"
 // @Param        address_id     path   int  true  "Address ID"
 // @Success      200  {object}  utils.MessageRes
 // @Failure      400  {object}  utils.MessageRes
 // @Failure      401  {object}  utils.MessageRes
 // @Failure      404  {object}  utils.MessageRes
-// @Router       /users/{id}/addresses/{address_id}/set-default [put]
+// @Router       /users/{user_id}/addresses/{address_id}/set-default [put]
 func (h *Handler) SetDefaultAddress(w http.ResponseWriter, r *http.Request) {
 	addressID, userID, err := h.getIDsFromParam(r)
 	if err != nil {
-		utils.WriterErrorResponse(w, http.StatusBadRequest, err.Error())
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	res, err := h.service.SetDefaultAddress(r.Context(), addressID, userID)
 	if err != nil {
-		utils.WriterErrorResponse(w, http.StatusNotFound, err.Error())
+		utils.WriteError(w, r, err)
 		return
 	}
 
@@ -223,23 +233,23 @@ func (h *Handler) SetDefaultAddress(w http.ResponseWriter, r *http.Request) {
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
-// @Param        id             path   int  true  "User ID"
+// @Param        user_id        path   int  true  "User ID"
 // @Param        address_id     path   int  true  "Address ID"
 // @Success      200  {object}  utils.MessageRes
 // @Failure      400  {object}  utils.MessageRes
 // @Failure      401  {object}  utils.MessageRes
 // @Failure      404  {object}  utils.MessageRes
-// @Router       /users/{id}/addresses/{address_id}/delete [delete]
+// @Router       /users/{user_id}/addresses/{address_id}/delete [delete]
 func (h *Handler) DeleteAddress(w http.ResponseWriter, r *http.Request) {
 	addressID, userID, err := h.getIDsFromParam(r)
 	if err != nil {
-		utils.WriterErrorResponse(w, http.StatusBadRequest, err.Error())
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	res, err := h.service.DeleteAddress(r.Context(), addressID, userID)
 	if err != nil {
-		utils.WriterErrorResponse(w, http.StatusNotFound, err.Error())
+		utils.WriteError(w, r, err)
 		return
 	}
 
@@ -4,6 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"time"
+
+	"github.com/aslam-ep/go-e-commerce/apperrors"
+	"github.com/aslam-ep/go-e-commerce/internal/pagination"
 )
 
 // Repository interface for auth repository
@@ -14,8 +17,8 @@ type Repository interface {
 	// GetCountByUserID Get the number of address created under given user ID
 	GetCountByUserID(ctx context.Context, userID int) (int, error)
 
-	// GetAll Get all the address created under the given user ID
-	GetAll(ctx context.Context, userID int) (*[]Address, error)
+	// GetAll Get a page of the address created under the given user ID, fetching one row past the page limit
+	GetAll(ctx context.Context, userID int, page *pagination.Page) (*[]Address, error)
 
 	// GetByID Get Address by the given ID and user ID
 	GetByID(ctx context.Context, id int, userID int) (*Address, error)
@@ -59,7 +62,7 @@ func (r *repository) Create(ctx context.Context, address *Address) (*Address, er
 	)
 
 	if err != nil {
-		return nil, err
+		return nil, apperrors.FromDB(err, "address")
 	}
 
 	return address, nil
@@ -77,16 +80,19 @@ func (r *repository) GetCountByUserID(ctx context.Context, userID int) (int, err
 	return count, nil
 }
 
-func (r *repository) GetAll(ctx context.Context, userID int) (*[]Address, error) {
-	selectByUserIDQuery := `SELECT id, user_id, address_line1, address_line2, postal_code, city, state, country, is_default, created_at, updated_at FROM addresses WHERE user_id = $1 ORDER BY id;`
+func (r *repository) GetAll(ctx context.Context, userID int, page *pagination.Page) (*[]Address, error) {
+	selectByUserIDQuery, args := page.Apply(
+		`SELECT id, user_id, address_line1, address_line2, postal_code, city, state, country, is_default, created_at, updated_at FROM addresses WHERE user_id = $1`,
+		[]any{userID},
+	)
 
-	rows, err := r.db.QueryContext(ctx, selectByUserIDQuery, userID)
+	rows, err := r.db.QueryContext(ctx, selectByUserIDQuery, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var addresses []Address
+	addresses := []Address{}
 	for rows.Next() {
 		var address Address
 		if err := rows.Scan(
@@ -134,7 +140,7 @@ func (r *repository) GetByID(ctx context.Context, id int, userID int) (*Address,
 	)
 
 	if err != nil {
-		return nil, err
+		return nil, apperrors.FromDB(err, "address")
 	}
 
 	return &address, nil
@@ -2,21 +2,28 @@ package address
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"time"
 
+	"github.com/aslam-ep/go-e-commerce/apperrors"
 	"github.com/aslam-ep/go-e-commerce/config"
+	"github.com/aslam-ep/go-e-commerce/internal/pagination"
 	"github.com/aslam-ep/go-e-commerce/utils"
 )
 
+// ErrAddressLimitReached is returned when the user already has the maximum number of addresses.
+var ErrAddressLimitReached = apperrors.LimitExceeded("address_limit_reached", "user can't have more than 10 addresses")
+
+// ListSorts are the sorts of the address list, the first one is the default.
+var ListSorts = []string{pagination.SortByID, "-" + pagination.SortByID, pagination.SortByCreatedAt, "-" + pagination.SortByCreatedAt}
+
 // Service interface defines the methods required for address services.
 type Service interface {
 	// CreateAdddress Creates a new address based on the provided request and returns the created address details.
 	CreateAddress(c context.Context, req *CreateUpdateAddressRequest) (*Address, error)
 
-	// GetAllAddress Get all address based on the given userID and returns the AdderessRes
-	GetAllAddress(c context.Context, userID int) (*ListAddressRes, error)
+	// GetAllAddress Get a page of address based on the given userID and returns the AdderessRes
+	GetAllAddress(c context.Context, userID int, params pagination.Params) (*ListAddressRes, error)
 
 	// GetAddressByID Get a address by the address and user ID and return it
 	GetAddressByID(c context.Context, id int, userID int) (*Address, error)
@@ -56,7 +63,7 @@ func (s *addressService) CreateAddress(c context.Context, req *CreateUpdateAddre
 	}
 
 	if count >= s.addressLimit {
-		return nil, errors.New("user can't have more than 10 addresses")
+		return nil, ErrAddressLimitReached
 	}
 
 	a := &Address{
@@ -77,18 +84,29 @@ func (s *addressService) CreateAddress(c context.Context, req *CreateUpdateAddre
 	return address, nil
 }
 
-func (s *addressService) GetAllAddress(c context.Context, userID int) (*ListAddressRes, error) {
+func (s *addressService) GetAllAddress(c context.Context, userID int, params pagination.Params) (*ListAddressRes, error) {
+	page, err := pagination.NewPage(params, ListSorts...)
+	if err != nil {
+		return nil, err
+	}
+
 	ctx, cancel := context.WithTimeout(c, s.timeout)
 	defer cancel()
 
-	addresses, err := s.repository.GetAll(ctx, userID)
+	addresses, err := s.repository.GetAll(ctx, userID, page)
 	if err != nil {
 		return nil, err
 	}
 
+	pageAddresses, info := pagination.Paginate(*addresses, page, func(a Address) pagination.Key {
+		return pagination.Key{ID: a.ID, CreatedAt: a.CreatedAt}
+	})
+
 	res := &ListAddressRes{
-		Count:     len(*addresses),
-		Addresses: addresses,
+		Count:      len(pageAddresses),
+		Addresses:  &pageAddresses,
+		NextCursor: info.NextCursor,
+		HasMore:    info.HasMore,
 	}
 
 	return res, nil
@@ -1,8 +1,108 @@
 package auth
 
-import "time"
+import (
+	"fmt"
+	"math"
+	"time"
 
-// RegisterUserReq represents the request payload for creating a new user.
+	"github.com/aslam-ep/go-e-commerce/apperrors"
+)
+
+var (
+	// ErrInvalidCredentials is returned when the email or password doesn't match.
+	ErrInvalidCredentials = apperrors.Unauthorized("invalid_credentials", "invalid credentials")
+
+	// ErrInvalidRefreshToken is returned when the refresh token doesn't exist or has expired.
+	ErrInvalidRefreshToken = apperrors.Unauthorized("invalid_refresh_token", "invalid refresh token")
+
+	// ErrRefreshTokenReused is returned when an already rotated refresh token is presented again.
+	ErrRefreshTokenReused = apperrors.Unauthorized("refresh_token_reused", "refresh token reused, all sessions of the token family revoked")
+
+	// ErrInvalidResetToken is returned when the password reset token doesn't exist, has been used or has expired.
+	ErrInvalidResetToken = apperrors.Validation("invalid_reset_token", "invalid or expired password reset token")
+
+	// ErrUnverifiedUser is returned on login when the email isn't verified and unverified logins aren't allowed.
+	ErrUnverifiedUser = apperrors.Forbidden("unverified_user", "verify your email before logging in")
+
+	// ErrInvalidVerificationCode is returned when the verification code doesn't match, has been used or has expired.
+	ErrInvalidVerificationCode = apperrors.Validation("invalid_verification_code", "invalid or expired verification code")
+
+	// ErrVerificationAttemptsExceeded is returned when the verification code has been tried too many times.
+	ErrVerificationAttemptsExceeded = apperrors.LimitExceeded("verification_attempts_exceeded", "too many attempts, request a new verification code")
+
+	// ErrVerificationRateLimited is returned when verification codes are requested too often.
+	ErrVerificationRateLimited = apperrors.LimitExceeded("verification_rate_limited", "verification code requested too often, try again later")
+
+	// ErrInvalidMFAToken is returned when the MFA challenge token is invalid, expired or already used.
+	ErrInvalidMFAToken = apperrors.Unauthorized("invalid_mfa_token", "invalid or expired MFA token")
+
+	// ErrInvalidMFACode is returned when the authenticator or recovery code doesn't match.
+	ErrInvalidMFACode = apperrors.Unauthorized("invalid_mfa_code", "invalid MFA code")
+
+	// ErrMFAAlreadyEnabled is returned when enrolling an account which already has two factor authentication.
+	ErrMFAAlreadyEnabled = apperrors.Conflict("mfa_already_enabled", "two factor authentication is already enabled")
+
+	// ErrMFANotEnabled is returned when confirming or disabling two factor authentication which isn't set up.
+	ErrMFANotEnabled = apperrors.Validation("mfa_not_enabled", "two factor authentication is not enrolled")
+
+	// ErrNoPhone is returned when verifying the phone of an account without one.
+	ErrNoPhone = apperrors.Validation("no_phone", "no phone number on the account")
+
+	// ErrUnknownProvider is returned when the social login provider isn't configured.
+	ErrUnknownProvider = apperrors.NotFound("unknown_provider", "unknown login provider")
+
+	// ErrInvalidOAuthState is returned when the social login state doesn't exist, has expired or belongs to another provider.
+	ErrInvalidOAuthState = apperrors.Validation("invalid_oauth_state", "invalid or expired login state")
+
+	// ErrSocialLoginFailed is returned when the provider denies the login or its tokens can't be verified.
+	ErrSocialLoginFailed = apperrors.Unauthorized("social_login_failed", "social login failed")
+
+	// ErrInvalidLoginCode is returned when the social login code doesn't exist, has expired or was already exchanged.
+	ErrInvalidLoginCode = apperrors.Unauthorized("invalid_login_code", "invalid or expired login code")
+
+	// ErrEmailNotProvided is returned when the provider doesn't share a verified email for a new account.
+	ErrEmailNotProvided = apperrors.Unauthorized("email_not_provided", "the provider didn't share a verified email")
+
+	// ErrAccountLinkRequired is returned when the provider's email belongs to an account whose email isn't verified, the
+	// account may have been registered by someone else so it isn't linked automatically.
+	ErrAccountLinkRequired = apperrors.Conflict("account_link_required", "an account with this email exists, verify its email before signing in with the provider")
+
+	// ErrInvalidEmailChangeToken is returned when the email change token doesn't exist, has been used or has expired.
+	ErrInvalidEmailChangeToken = apperrors.Validation("invalid_email_change_token", "invalid or expired email change token")
+
+	// ErrSameEmail is returned when the requested email is the current email of the user.
+	ErrSameEmail = apperrors.Validation("same_email", "new email is the same as the current email")
+)
+
+// LockedError is returned when logins of the account or the client are blocked after repeated failures.
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("too many failed login attempts, try again in %d seconds", e.RetryAfterSeconds())
+}
+
+// RetryAfterSeconds returns the wait before the next login attempt rounded up to whole seconds.
+func (e *LockedError) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+// Reasons recorded against failed login attempts.
+const (
+	LoginFailureUnknownEmail    = "unknown_email"
+	LoginFailureInvalidPassword = "invalid_password"
+	LoginFailureLocked          = "locked"
+	LoginFailureInvalidMFACode  = "invalid_mfa_code"
+)
+
+// Verification channels, a code is sent to the user's email or phone.
+const (
+	ChannelEmail = "email"
+	ChannelPhone = "phone"
+)
+
+// RegisterUserReq represents the request payload for creating a new user, the admin role can't be self assigned.
 type RegisterUserReq struct {
 	Name     string `json:"name" validate:"required,min=3,max=100"`
 	Email    string `json:"email" validate:"required,email"`
@@ -12,31 +112,239 @@ type RegisterUserReq struct {
 }
 
 // RefreshToken represents a refresh token issued to a user for renewing access tokens.
+// Every rotation issues a new token in the same family and revokes the presented one.
 type RefreshToken struct {
-	ID        int64     `json:"id"`
-	UserID    int64     `json:"user_id"`
-	Token     string    `json:"token"`
-	ExpiresAt time.Time `json:"expires_at"`
+	ID        int64      `json:"id"`
+	UserID    int64      `json:"user_id"`
+	Token     string     `json:"token"`
+	FamilyID  string     `json:"family_id"`
+	UserAgent string     `json:"user_agent"`
+	IPAddress string     `json:"ip_address"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ClientInfo holds the device details of the request, recorded against the session.
+type ClientInfo struct {
+	UserAgent string `json:"-"`
+	IPAddress string `json:"-"`
 }
 
 // LoginReq represents the request payload for user login.
 type LoginReq struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
+	ClientInfo
+}
+
+// LoginAttempt represents an audit record of a failed login, the user is unknown when the email isn't registered.
+type LoginAttempt struct {
+	ID        int64     `json:"id"`
+	UserID    *int64    `json:"user_id,omitempty"`
+	Email     string    `json:"email"`
+	IPAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 // LoginRes represents the response returned upon successful user login.
+// Accounts with two factor authentication get an MFA token to verify instead of the token pair.
 type LoginRes struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	MFARequired  bool   `json:"mfa_required,omitempty"`
+	MFAToken     string `json:"mfa_token,omitempty"`
+}
+
+// UserMFA represents the TOTP secret of a user, two factor authentication is enabled once the enrollment is confirmed.
+type UserMFA struct {
+	UserID       int64      `json:"user_id"`
+	Secret       string     `json:"-"`
+	EnabledAt    *time.Time `json:"enabled_at,omitempty"`
+	LastUsedStep int64      `json:"-"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// IsEnabled reports whether the enrollment has been confirmed.
+func (m *UserMFA) IsEnabled() bool {
+	return m.EnabledAt != nil
+}
+
+// MFAEnrollRes represents the response returned when enrolling in two factor authentication.
+type MFAEnrollRes struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// MFACodeReq represents the request payload carrying an authenticator or recovery code.
+type MFACodeReq struct {
+	UserID int64  `json:"-"`
+	Code   string `json:"code" validate:"required,min=6,max=20"`
+}
+
+// MFARecoveryCodesRes represents the recovery codes, shown only once when two factor authentication is enabled.
+type MFARecoveryCodesRes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// Identity represents an external provider account linked to a user.
+type Identity struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"subject"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// OAuthState represents a pending social login, holding the PKCE verifier and nonce until the callback.
+type OAuthState struct {
+	State        string    `json:"state"`
+	Provider     string    `json:"provider"`
+	CodeVerifier string    `json:"-"`
+	Nonce        string    `json:"-"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// OAuthLoginCode represents a completed social login waiting for the frontend to exchange it for the tokens.
+type OAuthLoginCode struct {
+	CodeHash  string    `json:"-"`
+	UserID    int64     `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// OIDCCallbackReq represents the authorization response of the provider redirected to the callback.
+// CookieState is the state stored in the browser that started the login, it must match the returned state.
+type OIDCCallbackReq struct {
+	Provider    string
+	Code        string
+	State       string
+	CookieState string
+	Error       string
+}
+
+// OIDCExchangeReq represents the request payload for exchanging the code of a social login for the tokens.
+type OIDCExchangeReq struct {
+	Code string `json:"code" validate:"required"`
+	ClientInfo
+}
+
+// VerifyMFAReq represents the request payload for completing a login with the second factor.
+type VerifyMFAReq struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required,min=6,max=20"`
+	ClientInfo
 }
 
 // RefreshTokenReq represents the request payload for refreshing an access token.
 type RefreshTokenReq struct {
 	RefreshToken string `json:"refresh_token" validate:"required"`
+	ClientInfo
+}
+
+// LogoutReq represents the request payload for ending the session of a refresh token.
+// The access token is taken from the authorization header when present and revoked as well.
+type LogoutReq struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+	AccessToken  string `json:"-"`
+}
+
+// Session represents an active refresh token along with the device it was issued to.
+type Session struct {
+	ID        int64     `json:"id"`
+	UserAgent string    `json:"user_agent"`
+	IPAddress string    `json:"ip_address"`
+	IssuedAt  time.Time `json:"issued_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ListSessionRes struct for returning set of active sessions
+type ListSessionRes struct {
+	Count    int        `json:"count"`
+	Sessions *[]Session `json:"sessions"`
+}
+
+// PasswordResetToken represents a single use token for resetting a forgotten password, only its hash is stored.
+type PasswordResetToken struct {
+	ID        int64      `json:"id"`
+	UserID    int64      `json:"user_id"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ForgotPasswordReq represents the request payload for requesting a password reset email.
+type ForgotPasswordReq struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ResetPasswordReq represents the request payload for setting a new password with a reset token.
+type ResetPasswordReq struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,min=6"`
+}
+
+// EmailChangeToken represents a single use token confirming a new email of the user, only its hash is stored.
+type EmailChangeToken struct {
+	ID        int64      `json:"id"`
+	UserID    int64      `json:"user_id"`
+	NewEmail  string     `json:"new_email"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ChangeEmailReq represents the request payload for changing the email of the authenticated user.
+type ChangeEmailReq struct {
+	UserID          int64  `json:"-"`
+	NewEmail        string `json:"new_email" validate:"required,email"`
+	CurrentPassword string `json:"current_password" validate:"required"`
+}
+
+// ConfirmEmailChangeReq represents the request payload for confirming a new email with the mailed token.
+type ConfirmEmailChangeReq struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// VerificationCode represents a code sent to the user's email or phone for verifying it, only its hash is stored.
+// The target is the email or phone the code was sent to, so the code is void once it changes.
+type VerificationCode struct {
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"user_id"`
+	Channel    string     `json:"channel"`
+	Target     string     `json:"target"`
+	CodeHash   string     `json:"-"`
+	Attempts   int        `json:"attempts"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	ConsumedAt *time.Time `json:"consumed_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// ConfirmVerificationReq represents the request payload for verifying an email or phone with the received code.
+type ConfirmVerificationReq struct {
+	Email   string `json:"email" validate:"required,email"`
+	Channel string `json:"channel" validate:"required,oneof=email phone"`
+	Code    string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// ResendVerificationReq represents the request payload for sending a new verification code.
+type ResendVerificationReq struct {
+	Email   string `json:"email" validate:"required,email"`
+	Channel string `json:"channel" validate:"required,oneof=email phone"`
+}
+
+// LockUserReq represents the request payload for locking a user out of login, at most for a year.
+type LockUserReq struct {
+	UserID  int64 `json:"-"`
+	Minutes int   `json:"minutes" validate:"required,gte=1,lte=525600"`
 }
 
 // RefreshTokenRes represents the response returned upon successful token refresh.
 type RefreshTokenRes struct {
-	AccessToken string `json:"access_token"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
 }
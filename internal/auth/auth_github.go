@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/aslam-ep/go-e-commerce/config"
+)
+
+// GitHub endpoints, GitHub is an OAuth2 provider without OpenID Connect so the account is read from its API
+const (
+	githubAuthURL  = "https://github.com/login/oauth/authorize"
+	githubTokenURL = "https://github.com/login/oauth/access_token"
+	githubAPIURL   = "https://api.github.com"
+)
+
+// GitHubProvider is a GitHub OAuth app client using the authorization code flow with PKCE.
+type GitHubProvider struct {
+	config     config.OIDCProviderConfig
+	httpClient *http.Client
+
+	authURL  string
+	tokenURL string
+	apiURL   string
+}
+
+// NewGitHubProvider initialize and returns the GitHub provider.
+func NewGitHubProvider(providerConfig config.OIDCProviderConfig) *GitHubProvider {
+	return &GitHubProvider{
+		config:     providerConfig,
+		httpClient: &http.Client{Timeout: providerTimeout},
+		authURL:    githubAuthURL,
+		tokenURL:   githubTokenURL,
+		apiURL:     githubAPIURL,
+	}
+}
+
+// AuthCodeURL returns GitHub's authorization URL for the state and PKCE challenge, GitHub has no nonce.
+func (p *GitHubProvider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	params := url.Values{}
+	params.Set("client_id", p.config.ClientID)
+	params.Set("redirect_uri", p.config.RedirectURL)
+	params.Set("scope", "read:user user:email")
+	params.Set("state", state)
+	params.Set("code_challenge", codeChallenge)
+	params.Set("code_challenge_method", "S256")
+
+	return p.authURL + "?" + params.Encode(), nil
+}
+
+// Exchange redeems the authorization code and returns the claims of the GitHub account, the subject is the
+// account id as the login can be renamed.
+func (p *GitHubProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*OIDCClaims, error) {
+	form := url.Values{}
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("client_id", p.config.ClientID)
+	form.Set("client_secret", p.config.ClientSecret)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	// GitHub reports a rejected code with a successful status and an error field
+	var tokenRes struct {
+		AccessToken string `json:"access_token"`
+		Error       string `json:"error"`
+	}
+	if err := doJSON(p.httpClient, req, &tokenRes); err != nil {
+		return nil, fmt.Errorf("token exchange failed: %v", err)
+	}
+	if tokenRes.Error != "" {
+		return nil, fmt.Errorf("token exchange failed: %s", tokenRes.Error)
+	}
+	if tokenRes.AccessToken == "" {
+		return nil, errors.New("token response has no access token")
+	}
+
+	var account struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+		Name  string `json:"name"`
+	}
+	if err := p.getAPI(ctx, tokenRes.AccessToken, "/user", &account); err != nil {
+		return nil, fmt.Errorf("failed to fetch account: %v", err)
+	}
+	if account.ID == 0 {
+		return nil, errors.New("account has no id")
+	}
+
+	// The profile email is optional and unverified, the primary email is read from the emails list instead
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := p.getAPI(ctx, tokenRes.AccessToken, "/user/emails", &emails); err != nil {
+		return nil, fmt.Errorf("failed to fetch account emails: %v", err)
+	}
+
+	claims := &OIDCClaims{
+		Subject: strconv.FormatInt(account.ID, 10),
+		Name:    account.Name,
+	}
+	if claims.Name == "" {
+		claims.Name = account.Login
+	}
+
+	for _, email := range emails {
+		if email.Primary {
+			claims.Email = email.Email
+			claims.EmailVerified = email.Verified
+			break
+		}
+	}
+
+	return claims, nil
+}
+
+// getAPI reads the resource of the GitHub API with the user's access token
+func (p *GitHubProvider) getAPI(ctx context.Context, accessToken, path string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.apiURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	return doJSON(p.httpClient, req, v)
+}
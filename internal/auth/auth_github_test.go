@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/aslam-ep/go-e-commerce/config"
+)
+
+func TestGitHubProviderExchange(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+
+		// Rejected codes are reported with a successful status like GitHub does
+		if r.PostForm.Get("code") != "code" || CodeChallenge(r.PostForm.Get("code_verifier")) != CodeChallenge("verifier") {
+			json.NewEncoder(w).Encode(map[string]string{"error": "bad_verification_code"})
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "access-token", "token_type": "bearer"})
+	})
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]any{"id": 42, "login": "jane", "email": "public@example.com"})
+	})
+	mux.HandleFunc("/user/emails", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]map[string]any{
+			{"email": "old@example.com", "primary": false, "verified": true},
+			{"email": "jane@example.com", "primary": true, "verified": true},
+		})
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	provider := NewGitHubProvider(config.OIDCProviderConfig{Name: "github", Type: "github", ClientID: testClientID, ClientSecret: testClientSecret})
+	provider.authURL = server.URL + "/login/oauth/authorize"
+	provider.tokenURL = server.URL + "/login/oauth/access_token"
+	provider.apiURL = server.URL
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state", "nonce", CodeChallenge("verifier"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query := parsed.Query(); query.Get("state") != "state" || query.Get("code_challenge") != CodeChallenge("verifier") || query.Get("code_challenge_method") != "S256" {
+		t.Fatalf("unexpected authorization URL %q", authURL)
+	}
+
+	claims, err := provider.Exchange(context.Background(), "code", "verifier", "nonce")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Subject != "42" || claims.Name != "jane" || claims.Email != "jane@example.com" || !claims.EmailVerified {
+		t.Fatalf("unexpected claims %+v", claims)
+	}
+
+	if _, err := provider.Exchange(context.Background(), "code", "other", "nonce"); err == nil {
+		t.Fatal("expected the exchange with the wrong verifier to fail")
+	}
+}
@@ -1,11 +1,25 @@
 package auth
 
 import (
+	"errors"
+	"log"
+	"net"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
+	"github.com/go-chi/chi/v5"
+
+	"github.com/aslam-ep/go-e-commerce/apperrors"
+	"github.com/aslam-ep/go-e-commerce/config"
+	"github.com/aslam-ep/go-e-commerce/router/middleware"
 	"github.com/aslam-ep/go-e-commerce/utils"
 )
 
+// oauthStateCookie holds the state of the social login started by the browser
+const oauthStateCookie = "oauth_state"
+
 // Handler handles HTTP requests related to authentication.
 type Handler struct {
 	service Service
@@ -18,6 +32,24 @@ func NewHandler(as Service) *Handler {
 	}
 }
 
+// getClientInfo reads the device details of the request for recording the session
+func (h *Handler) getClientInfo(r *http.Request) ClientInfo {
+	userAgent := r.UserAgent()
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	ipAddress, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ipAddress = r.RemoteAddr
+	}
+
+	return ClientInfo{
+		UserAgent: userAgent,
+		IPAddress: ipAddress,
+	}
+}
+
 // Register      godoc
 // @Summary      Register a new user
 // @Description  Register a new user with the provided details
@@ -31,27 +63,40 @@ func NewHandler(as Service) *Handler {
 func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	var registerUserReq RegisterUserReq
 	if err := utils.ReadFromRequest(r, &registerUserReq); err != nil {
-		utils.WriterErrorResponse(w, http.StatusBadRequest, err.Error())
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	if err := utils.Validate.Struct(registerUserReq); err != nil {
-		utils.WriterErrorResponse(w, http.StatusBadRequest, err.Error())
+		utils.WriteValidationError(w, r, err)
 		return
 	}
 
 	res, err := h.service.RegisterUser(r.Context(), &registerUserReq)
 	if err != nil {
-		utils.WriterErrorResponse(w, http.StatusInternalServerError, err.Error())
+		utils.WriteError(w, r, err)
 		return
 	}
 
 	utils.WriteResponse(w, http.StatusOK, res)
 }
 
+// writeLoginError writes the response status matching the login error
+func (h *Handler) writeLoginError(w http.ResponseWriter, r *http.Request, err error) {
+	var lockedErr *LockedError
+
+	switch {
+	case errors.As(err, &lockedErr):
+		w.Header().Set("Retry-After", strconv.Itoa(lockedErr.RetryAfterSeconds()))
+		utils.WriterErrorResponse(w, r, http.StatusTooManyRequests, err.Error())
+	default:
+		utils.WriteError(w, r, err)
+	}
+}
+
 // Login         godoc
 // @Summary      Login user
-// @Description  Login a user, on success get the refreshToken and accessToken
+// @Description  Login a user, on success get the refreshToken and accessToken, or an mfaToken to verify when two factor authentication is enabled
 // @Tags         Auth
 // @Accept       json
 // @Produce      json
@@ -59,17 +104,21 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 // @Success      200  {object}  LoginRes "Login response"
 // @Failure      400  {object}  utils.MessageRes "Default response"
 // @Failure      401  {object}  utils.MessageRes "Default response"
+// @Failure      403  {object}  utils.MessageRes "Default response"
+// @Failure      429  {object}  utils.MessageRes "Default response"
+// @Failure      500  {object}  utils.MessageRes "Default response"
 // @Router       /auth/login [post]
 func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	var req LoginReq
 	if err := utils.ReadFromRequest(r, &req); err != nil {
-		utils.WriterErrorResponse(w, http.StatusBadRequest, err.Error())
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
+	req.ClientInfo = h.getClientInfo(r)
 
 	res, err := h.service.Authenticate(r.Context(), &req)
 	if err != nil {
-		utils.WriterErrorResponse(w, http.StatusUnauthorized, err.Error())
+		h.writeLoginError(w, r, err)
 		return
 	}
 
@@ -78,7 +127,7 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 
 // RefreshToken  godoc
 // @Summary      Refresh token
-// @Description  Refresh token, send the new access token based on refresh token
+// @Description  Refresh token, rotates the refresh token and sends the new token pair, reusing a rotated token revokes its family
 // @Tags         Auth
 // @Accept       json
 // @Produce      json
@@ -90,13 +139,724 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	var req RefreshTokenReq
 	if err := utils.ReadFromRequest(r, &req); err != nil {
-		utils.WriterErrorResponse(w, http.StatusBadRequest, err.Error())
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
+	req.ClientInfo = h.getClientInfo(r)
 
 	res, err := h.service.RefreshToken(r.Context(), &req)
 	if err != nil {
-		utils.WriterErrorResponse(w, http.StatusUnauthorized, err.Error())
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusAccepted, res)
+}
+
+// Logout        godoc
+// @Summary      Logout
+// @Description  Logout by revoking the session of the given refresh token and the bearer access token when present
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        body  body  LogoutReq  true  "Logout request"
+// @Success      200  {object}  utils.MessageRes "Default response"
+// @Failure      400  {object}  utils.MessageRes "Default response"
+// @Failure      401  {object}  utils.MessageRes "Default response"
+// @Router       /auth/logout [post]
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	var req LogoutReq
+	if err := utils.ReadFromRequest(r, &req); err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.AccessToken = strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.WriteValidationError(w, r, err)
+		return
+	}
+
+	res, err := h.service.Logout(r.Context(), &req)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// LogoutAll     godoc
+// @Summary      Logout from all devices
+// @Description  Logout the authenticated user from all devices by revoking every session
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  utils.MessageRes "Default response"
+// @Failure      401  {object}  utils.MessageRes "Default response"
+// @Failure      500  {object}  utils.MessageRes "Default response"
+// @Router       /auth/logout-all [post]
+func (h *Handler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	res, err := h.service.LogoutAll(r.Context(), userID)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// ListSessions  godoc
+// @Summary      List sessions
+// @Description  List the active sessions of the authenticated user with device details and issue time
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  ListSessionRes "Sessions response"
+// @Failure      401  {object}  utils.MessageRes "Default response"
+// @Failure      500  {object}  utils.MessageRes "Default response"
+// @Router       /auth/sessions [get]
+func (h *Handler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	res, err := h.service.ListSessions(r.Context(), userID)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// JWKS  godoc
+// @Summary      JSON web key set
+// @Description  Public keys for verifying access tokens, looked up by the kid header of the token
+// @Tags         Auth
+// @Produce      json
+// @Success      200  {object}  utils.JWKS "Key set response"
+// @Router       /.well-known/jwks.json [get]
+func (h *Handler) JWKS(w http.ResponseWriter, r *http.Request) {
+	// Allow verifiers to cache the keys, rotation keeps the previous key published for a while
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	utils.WriteResponse(w, http.StatusOK, h.service.GetJWKS())
+}
+
+// ForgotPassword godoc
+// @Summary      Forgot password
+// @Description  Mail a single use password reset link to the email, the response is the same for unregistered and throttled emails
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        body  body  ForgotPasswordReq  true  "Forgot password request"
+// @Success      200  {object}  utils.MessageRes "Default response"
+// @Failure      400  {object}  utils.MessageRes "Default response"
+// @Failure      429  {object}  utils.MessageRes "Default response"
+// @Failure      500  {object}  utils.MessageRes "Default response"
+// @Router       /auth/forgot-password [post]
+func (h *Handler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req ForgotPasswordReq
+	if err := utils.ReadFromRequest(r, &req); err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.WriteValidationError(w, r, err)
+		return
+	}
+
+	res, err := h.service.ForgotPassword(r.Context(), &req)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// ResetPassword godoc
+// @Summary      Reset password
+// @Description  Set a new password with the token from the reset email, all sessions of the user are revoked
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        body  body  ResetPasswordReq  true  "Reset password request"
+// @Success      200  {object}  utils.MessageRes "Default response"
+// @Failure      400  {object}  utils.MessageRes "Default response"
+// @Failure      500  {object}  utils.MessageRes "Default response"
+// @Router       /auth/reset-password [post]
+func (h *Handler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req ResetPasswordReq
+	if err := utils.ReadFromRequest(r, &req); err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.WriteValidationError(w, r, err)
+		return
+	}
+
+	res, err := h.service.ResetPassword(r.Context(), &req)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// ChangeEmail   godoc
+// @Summary      Change email
+// @Description  Mail a confirmation link to the new email and a notice to the current one, the email changes once confirmed
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body  ChangeEmailReq  true  "Change email request"
+// @Success      200  {object}  utils.MessageRes "Default response"
+// @Failure      400  {object}  utils.MessageRes "Default response"
+// @Failure      401  {object}  utils.MessageRes "Default response"
+// @Failure      409  {object}  utils.MessageRes "Default response"
+// @Failure      500  {object}  utils.MessageRes "Default response"
+// @Router       /auth/email/change [post]
+func (h *Handler) ChangeEmail(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var req ChangeEmailReq
+	if err := utils.ReadFromRequest(r, &req); err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.WriteValidationError(w, r, err)
+		return
+	}
+
+	req.UserID = int64(userID)
+
+	res, err := h.service.RequestEmailChange(r.Context(), &req)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// ConfirmEmailChange godoc
+// @Summary      Confirm email change
+// @Description  Confirm the new email with the token from the confirmation email, all sessions of the user are revoked
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        body  body  ConfirmEmailChangeReq  true  "Confirm email change request"
+// @Success      200  {object}  utils.MessageRes "Default response"
+// @Failure      400  {object}  utils.MessageRes "Default response"
+// @Failure      409  {object}  utils.MessageRes "Default response"
+// @Failure      500  {object}  utils.MessageRes "Default response"
+// @Router       /auth/email/confirm [post]
+func (h *Handler) ConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	var req ConfirmEmailChangeReq
+	if err := utils.ReadFromRequest(r, &req); err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.WriteValidationError(w, r, err)
+		return
+	}
+
+	res, err := h.service.ConfirmEmailChange(r.Context(), &req)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// ConfirmVerification godoc
+// @Summary      Confirm verification
+// @Description  Verify the email or phone of the account with the code sent to it, a code allows a limited number of attempts
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        body  body  ConfirmVerificationReq  true  "Confirm verification request"
+// @Success      200  {object}  utils.MessageRes "Default response"
+// @Failure      400  {object}  utils.MessageRes "Default response"
+// @Failure      429  {object}  utils.MessageRes "Default response"
+// @Failure      500  {object}  utils.MessageRes "Default response"
+// @Router       /auth/verify/confirm [post]
+func (h *Handler) ConfirmVerification(w http.ResponseWriter, r *http.Request) {
+	var req ConfirmVerificationReq
+	if err := utils.ReadFromRequest(r, &req); err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.WriteValidationError(w, r, err)
+		return
+	}
+
+	res, err := h.service.ConfirmVerification(r.Context(), &req)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// ResendVerification godoc
+// @Summary      Resend verification code
+// @Description  Send a new verification code to the email or phone of the account, codes can be resent once a minute
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        body  body  ResendVerificationReq  true  "Resend verification request"
+// @Success      200  {object}  utils.MessageRes "Default response"
+// @Failure      400  {object}  utils.MessageRes "Default response"
+// @Failure      429  {object}  utils.MessageRes "Default response"
+// @Failure      500  {object}  utils.MessageRes "Default response"
+// @Router       /auth/verify/resend [post]
+func (h *Handler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	var req ResendVerificationReq
+	if err := utils.ReadFromRequest(r, &req); err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.WriteValidationError(w, r, err)
+		return
+	}
+
+	res, err := h.service.ResendVerification(r.Context(), &req)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// UnlockUser    godoc
+// @Summary      Unlock user
+// @Description  Clear the failed logins and the lockout of the user, admin only
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        user_id  path  int  true  "User ID"
+// @Success      200  {object}  utils.MessageRes "Default response"
+// @Failure      400  {object}  utils.MessageRes "Default response"
+// @Failure      401  {object}  utils.MessageRes "Default response"
+// @Failure      403  {object}  utils.MessageRes "Default response"
+// @Failure      404  {object}  utils.MessageRes "Default response"
+// @Failure      500  {object}  utils.MessageRes "Default response"
+// @Router       /admin/users/{user_id}/unlock [put]
+func (h *Handler) UnlockUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "user_id"))
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.UnlockUser(r.Context(), userID)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// LockUser      godoc
+// @Summary      Lock user
+// @Description  Lock the user out of login for the given minutes and end all of their sessions, admin only
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        user_id  path  int          true  "User ID"
+// @Param        body     body  LockUserReq  true  "Lock duration"
+// @Success      200  {object}  utils.MessageRes "Default response"
+// @Failure      400  {object}  utils.MessageRes "Default response"
+// @Failure      401  {object}  utils.MessageRes "Default response"
+// @Failure      403  {object}  utils.MessageRes "Default response"
+// @Failure      404  {object}  utils.MessageRes "Default response"
+// @Failure      500  {object}  utils.MessageRes "Default response"
+// @Router       /admin/users/{user_id}/lock [put]
+func (h *Handler) LockUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "user_id"))
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req LockUserReq
+	if err := utils.ReadFromRequest(r, &req); err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.WriteValidationError(w, r, err)
+		return
+	}
+
+	req.UserID = int64(userID)
+
+	res, err := h.service.LockUser(r.Context(), &req)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// ForceLogout   godoc
+// @Summary      Force logout user
+// @Description  End every session of the user and revoke their access tokens, admin only
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        user_id  path  int  true  "User ID"
+// @Success      200  {object}  utils.MessageRes "Default response"
+// @Failure      400  {object}  utils.MessageRes "Default response"
+// @Failure      401  {object}  utils.MessageRes "Default response"
+// @Failure      403  {object}  utils.MessageRes "Default response"
+// @Failure      404  {object}  utils.MessageRes "Default response"
+// @Failure      500  {object}  utils.MessageRes "Default response"
+// @Router       /admin/users/{user_id}/logout [put]
+func (h *Handler) ForceLogout(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "user_id"))
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.ForceLogout(r.Context(), userID)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// writeMFAError writes the response status matching the two factor authentication setup error
+func (h *Handler) writeMFAError(w http.ResponseWriter, r *http.Request, err error) {
+	// A wrong code while managing MFA is a bad request, not a failed login
+	if errors.Is(err, ErrInvalidMFACode) {
+		utils.WriteError(w, r, apperrors.Validation(ErrInvalidMFACode.Code, ErrInvalidMFACode.Message))
+		return
+	}
+
+	// Wrong codes lock the account like failed logins
+	h.writeLoginError(w, r, err)
+}
+
+// readMFACodeReq reads and validates the code of the authenticated user's request
+func (h *Handler) readMFACodeReq(w http.ResponseWriter, r *http.Request) (*MFACodeReq, bool) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusUnauthorized, err.Error())
+		return nil, false
+	}
+
+	var req MFACodeReq
+	if err := utils.ReadFromRequest(r, &req); err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	req.UserID = int64(userID)
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.WriteValidationError(w, r, err)
+		return nil, false
+	}
+
+	return &req, true
+}
+
+// VerifyMFA     godoc
+// @Summary      Verify MFA login
+// @Description  Complete the login with the mfaToken and an authenticator or recovery code, on success get the refreshToken and accessToken
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        body  body  VerifyMFAReq  true  "Verify MFA request"
+// @Success      200  {object}  LoginRes "Login response"
+// @Failure      400  {object}  utils.MessageRes "Default response"
+// @Failure      401  {object}  utils.MessageRes "Default response"
+// @Failure      429  {object}  utils.MessageRes "Default response"
+// @Failure      500  {object}  utils.MessageRes "Default response"
+// @Router       /auth/mfa/verify [post]
+func (h *Handler) VerifyMFA(w http.ResponseWriter, r *http.Request) {
+	var req VerifyMFAReq
+	if err := utils.ReadFromRequest(r, &req); err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.ClientInfo = h.getClientInfo(r)
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.WriteValidationError(w, r, err)
+		return
+	}
+
+	res, err := h.service.VerifyMFA(r.Context(), &req)
+	if err != nil {
+		h.writeLoginError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusAccepted, res)
+}
+
+// EnrollMFA     godoc
+// @Summary      Enroll MFA
+// @Description  Generate a TOTP secret and otpauth URI for the authenticator app, enabled once confirmed with a code
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  MFAEnrollRes "MFA enroll response"
+// @Failure      401  {object}  utils.MessageRes "Default response"
+// @Failure      409  {object}  utils.MessageRes "Default response"
+// @Failure      500  {object}  utils.MessageRes "Default response"
+// @Router       /auth/mfa/enroll [post]
+func (h *Handler) EnrollMFA(w http.ResponseWriter, r *http.Request) {
+	userID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	res, err := h.service.EnrollMFA(r.Context(), userID)
+	if err != nil {
+		h.writeMFAError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// ConfirmMFA    godoc
+// @Summary      Confirm MFA
+// @Description  Enable two factor authentication with a code from the authenticator app, the recovery codes are shown only once
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body  MFACodeReq  true  "MFA code request"
+// @Success      200  {object}  MFARecoveryCodesRes "Recovery codes response"
+// @Failure      400  {object}  utils.MessageRes "Default response"
+// @Failure      401  {object}  utils.MessageRes "Default response"
+// @Failure      409  {object}  utils.MessageRes "Default response"
+// @Failure      429  {object}  utils.MessageRes "Default response"
+// @Failure      500  {object}  utils.MessageRes "Default response"
+// @Router       /auth/mfa/confirm [post]
+func (h *Handler) ConfirmMFA(w http.ResponseWriter, r *http.Request) {
+	req, ok := h.readMFACodeReq(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.ConfirmMFA(r.Context(), req)
+	if err != nil {
+		h.writeMFAError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// DisableMFA    godoc
+// @Summary      Disable MFA
+// @Description  Disable two factor authentication with an authenticator or recovery code
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body  MFACodeReq  true  "MFA code request"
+// @Success      200  {object}  utils.MessageRes "Default response"
+// @Failure      400  {object}  utils.MessageRes "Default response"
+// @Failure      401  {object}  utils.MessageRes "Default response"
+// @Failure      429  {object}  utils.MessageRes "Default response"
+// @Failure      500  {object}  utils.MessageRes "Default response"
+// @Router       /auth/mfa/disable [post]
+func (h *Handler) DisableMFA(w http.ResponseWriter, r *http.Request) {
+	req, ok := h.readMFACodeReq(w, r)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.DisableMFA(r.Context(), req)
+	if err != nil {
+		h.writeMFAError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// OIDCLogin     godoc
+// @Summary      Social login
+// @Description  Redirect to the login provider for signing in using the authorization code flow with PKCE, the login state is kept in a cookie of the browser
+// @Tags         Auth
+// @Param        provider  path  string  true  "Provider name"
+// @Success      302
+// @Failure      404  {object}  utils.MessageRes "Default response"
+// @Failure      500  {object}  utils.MessageRes "Default response"
+// @Router       /auth/oidc/{provider}/login [get]
+func (h *Handler) OIDCLogin(w http.ResponseWriter, r *http.Request) {
+	authURL, state, err := h.service.StartOIDCLogin(r.Context(), chi.URLParam(r, "provider"))
+	if err != nil {
+		h.writeLoginError(w, r, err)
+		return
+	}
+
+	// The cookie is scoped to the provider's routes, lax so it is sent along the provider's redirect to the callback
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthStateCookie,
+		Value:    state,
+		Path:     strings.TrimSuffix(r.URL.Path, "/login"),
+		MaxAge:   int(oauthStateExpiry.Seconds()),
+		HttpOnly: true,
+		Secure:   config.AppConfig.AppEnv != "development",
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// OIDCCallback  godoc
+// @Summary      Social login callback
+// @Description  Complete the social login with the authorization response, an account is linked when both the provider and the account verified the email, or created on first login.
+// @Description  Redirects to the frontend with a single use code in the URL fragment to exchange for the tokens, or the error code on failure
+// @Tags         Auth
+// @Param        provider  path   string  true   "Provider name"
+// @Param        code      query  string  false  "Authorization code"
+// @Param        state     query  string  true   "Login state"
+// @Success      302
+// @Router       /auth/oidc/{provider}/callback [get]
+func (h *Handler) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := OIDCCallbackReq{
+		Provider: chi.URLParam(r, "provider"),
+		Code:     query.Get("code"),
+		State:    query.Get("state"),
+		Error:    query.Get("error"),
+	}
+
+	if cookie, err := r.Cookie(oauthStateCookie); err == nil {
+		req.CookieState = cookie.Value
+	}
+
+	// The state is single use, so the cookie is cleared whatever the outcome
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthStateCookie,
+		Path:     strings.TrimSuffix(r.URL.Path, "/callback"),
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   config.AppConfig.AppEnv != "development",
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	result := url.Values{}
+	if req.State == "" || (req.Code == "" && req.Error == "") {
+		result.Set("error", ErrInvalidOAuthState.Code)
+		h.redirectOIDCResult(w, r, result)
+		return
+	}
+
+	code, err := h.service.CompleteOIDCLogin(r.Context(), &req)
+	if err != nil {
+		result.Set("error", oidcErrorCode(err))
+		h.redirectOIDCResult(w, r, result)
+		return
+	}
+
+	result.Set("code", code)
+	h.redirectOIDCResult(w, r, result)
+}
+
+// redirectOIDCResult redirects to the social login page of the frontend, the result is passed in the URL fragment
+// which browsers don't send to servers or in the referrer
+func (h *Handler) redirectOIDCResult(w http.ResponseWriter, r *http.Request, result url.Values) {
+	http.Redirect(w, r, config.AppConfig.AppURL+"/oauth/callback#"+result.Encode(), http.StatusFound)
+}
+
+// oidcErrorCode returns the error code of a failed social login reported to the frontend
+func oidcErrorCode(err error) string {
+	var lockedErr *LockedError
+	var appErr *apperrors.Error
+
+	switch {
+	case errors.As(err, &lockedErr):
+		return "account_locked"
+	case errors.As(err, &appErr):
+		return appErr.Code
+	default:
+		log.Println("Internal error:", err)
+		return "internal_error"
+	}
+}
+
+// OIDCExchange  godoc
+// @Summary      Social login token exchange
+// @Description  Exchange the single use code of a completed social login for the refreshToken and accessToken, or an mfaToken to verify when two factor authentication is enabled
+// @Tags         Auth
+// @Accept       json
+// @Produce      json
+// @Param        body  body  OIDCExchangeReq  true  "Social login exchange request"
+// @Success      200  {object}  LoginRes "Login response"
+// @Failure      400  {object}  utils.MessageRes "Default response"
+// @Failure      401  {object}  utils.MessageRes "Default response"
+// @Failure      403  {object}  utils.MessageRes "Default response"
+// @Failure      429  {object}  utils.MessageRes "Default response"
+// @Failure      500  {object}  utils.MessageRes "Default response"
+// @Router       /auth/oidc/exchange [post]
+func (h *Handler) OIDCExchange(w http.ResponseWriter, r *http.Request) {
+	var req OIDCExchangeReq
+	if err := utils.ReadFromRequest(r, &req); err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.ClientInfo = h.getClientInfo(r)
+
+	if err := utils.Validate.Struct(req); err != nil {
+		utils.WriteValidationError(w, r, err)
+		return
+	}
+
+	res, err := h.service.ExchangeOIDCLoginCode(r.Context(), &req)
+	if err != nil {
+		h.writeLoginError(w, r, err)
 		return
 	}
 
@@ -0,0 +1,280 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+
+	"github.com/aslam-ep/go-e-commerce/config"
+	"github.com/aslam-ep/go-e-commerce/utils"
+)
+
+const (
+	// jwksRefreshInterval limits how often the provider keys are fetched again for an unknown kid
+	jwksRefreshInterval = time.Minute * 5
+
+	// providerTimeout bounds every call to a login provider
+	providerTimeout = time.Second * 10
+)
+
+// OIDCClaims holds the identity claims of a verified ID token.
+type OIDCClaims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// oidcDiscovery holds the endpoints of the provider's discovery document
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// SocialProvider signs users in with their account of an external provider using the authorization code flow with PKCE.
+type SocialProvider interface {
+	// AuthCodeURL returns the provider's authorization URL for the state, nonce and PKCE challenge.
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+
+	// Exchange redeems the authorization code and returns the identity claims of the signed in account.
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*OIDCClaims, error)
+}
+
+// OIDCProvider is an OpenID Connect client using the authorization code flow with PKCE.
+type OIDCProvider struct {
+	config     config.OIDCProviderConfig
+	httpClient *http.Client
+
+	mu            sync.Mutex
+	discovery     *oidcDiscovery
+	keys          *utils.KeySet
+	keysFetchedAt time.Time
+}
+
+// NewSocialProviders initialize and returns the configured providers by their name, an error is returned for
+// providers of an unknown type.
+func NewSocialProviders(configs []config.OIDCProviderConfig) (map[string]SocialProvider, error) {
+	providers := make(map[string]SocialProvider, len(configs))
+	for _, providerConfig := range configs {
+		switch providerConfig.Type {
+		case "oidc":
+			providers[providerConfig.Name] = NewOIDCProvider(providerConfig)
+		case "github":
+			providers[providerConfig.Name] = NewGitHubProvider(providerConfig)
+		default:
+			return nil, fmt.Errorf("unknown type %q of login provider %s, expected oidc or github", providerConfig.Type, providerConfig.Name)
+		}
+	}
+
+	return providers, nil
+}
+
+// NewOIDCProvider initialize and returns the OpenID Connect provider, its endpoints are discovered from the issuer.
+func NewOIDCProvider(providerConfig config.OIDCProviderConfig) *OIDCProvider {
+	return &OIDCProvider{
+		config:     providerConfig,
+		httpClient: &http.Client{Timeout: providerTimeout},
+	}
+}
+
+// CodeChallenge returns the S256 PKCE challenge of the code verifier.
+func CodeChallenge(codeVerifier string) string {
+	hash := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+// AuthCodeURL returns the provider's authorization URL for the state, nonce and PKCE challenge.
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.config.ClientID)
+	params.Set("redirect_uri", p.config.RedirectURL)
+	params.Set("scope", "openid email profile")
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", codeChallenge)
+	params.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return discovery.AuthorizationEndpoint + separator + params.Encode(), nil
+}
+
+// Exchange redeems the authorization code and returns the claims of the verified ID token.
+func (p *OIDCProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*OIDCClaims, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("client_id", p.config.ClientID)
+	form.Set("client_secret", p.config.ClientSecret)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var tokenRes struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := doJSON(p.httpClient, req, &tokenRes); err != nil {
+		return nil, fmt.Errorf("token exchange failed: %v", err)
+	}
+	if tokenRes.IDToken == "" {
+		return nil, errors.New("token response has no id token")
+	}
+
+	return p.verifyIDToken(ctx, discovery, tokenRes.IDToken, nonce)
+}
+
+// verifyIDToken checks the signature, issuer, audience, expiry and nonce of the ID token
+func (p *OIDCProvider) verifyIDToken(ctx context.Context, discovery *oidcDiscovery, idToken, nonce string) (*OIDCClaims, error) {
+	keys, err := p.getKeys(ctx, discovery, idToken)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := utils.ValidateToken(idToken, keys)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id token: %v", err)
+	}
+
+	if iss, _ := claims["iss"].(string); iss != discovery.Issuer {
+		return nil, errors.New("id token issuer mismatch")
+	}
+	if !claims.VerifyAudience(p.config.ClientID, true) {
+		return nil, errors.New("id token audience mismatch")
+	}
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, errors.New("id token expired")
+	}
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+		return nil, errors.New("id token nonce mismatch")
+	}
+
+	oidcClaims := &OIDCClaims{}
+	oidcClaims.Subject, _ = claims["sub"].(string)
+	oidcClaims.Email, _ = claims["email"].(string)
+	oidcClaims.Name, _ = claims["name"].(string)
+
+	// Some providers send email_verified as a string
+	switch verified := claims["email_verified"].(type) {
+	case bool:
+		oidcClaims.EmailVerified = verified
+	case string:
+		oidcClaims.EmailVerified = verified == "true"
+	}
+
+	if oidcClaims.Subject == "" {
+		return nil, errors.New("id token has no subject")
+	}
+
+	return oidcClaims, nil
+}
+
+// getDiscovery fetches the discovery document of the issuer once
+func (p *OIDCProvider) getDiscovery(ctx context.Context) (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	discoveryURL := strings.TrimSuffix(p.config.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var discovery oidcDiscovery
+	if err := doJSON(p.httpClient, req, &discovery); err != nil {
+		return nil, fmt.Errorf("failed to discover provider %s: %v", p.config.Name, err)
+	}
+
+	if discovery.Issuer != p.config.Issuer {
+		return nil, fmt.Errorf("provider %s discovery issuer mismatch", p.config.Name)
+	}
+
+	p.discovery = &discovery
+
+	return p.discovery, nil
+}
+
+// getKeys returns the provider's signing keys, fetching them again when the token is signed by an unknown key
+func (p *OIDCProvider) getKeys(ctx context.Context, discovery *oidcDiscovery, idToken string) (*utils.KeySet, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	kid := ""
+	if token, _, err := new(jwt.Parser).ParseUnverified(idToken, jwt.MapClaims{}); err == nil {
+		kid, _ = token.Header["kid"].(string)
+	}
+
+	if p.keys != nil && (p.keys.HasKey(kid) || time.Since(p.keysFetchedAt) < jwksRefreshInterval) {
+		return p.keys, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discovery.JWKSURI, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var jwks utils.JWKS
+	if err := doJSON(p.httpClient, req, &jwks); err != nil {
+		return nil, fmt.Errorf("failed to fetch provider keys: %v", err)
+	}
+
+	keys, err := utils.NewKeySetFromJWKS(&jwks)
+	if err != nil {
+		return nil, err
+	}
+
+	p.keys = keys
+	p.keysFetchedAt = time.Now()
+
+	return p.keys, nil
+}
+
+// doJSON sends the request and decodes the JSON response body
+func doJSON(client *http.Client, req *http.Request, v any) error {
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", res.StatusCode)
+	}
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
@@ -0,0 +1,463 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt"
+
+	"github.com/aslam-ep/go-e-commerce/config"
+	"github.com/aslam-ep/go-e-commerce/internal/user"
+	"github.com/aslam-ep/go-e-commerce/utils"
+)
+
+const (
+	testClientID     = "go-e-commerce"
+	testClientSecret = "secret"
+	testRedirectURL  = "http://api.test/auth/oidc/mock/callback"
+	testSubject      = "subject-1"
+)
+
+// mockOIDCServer is a local OpenID Connect provider redirecting straight back with a code, it checks the PKCE
+// verifier and signs ID tokens carrying the nonce of the authorization request
+type mockOIDCServer struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]url.Values
+}
+
+func newMockOIDCServer(t *testing.T) *mockOIDCServer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := &mockOIDCServer{key: key, codes: make(map[string]url.Values)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", m.discovery)
+	mux.HandleFunc("/authorize", m.authorize)
+	mux.HandleFunc("/token", m.token)
+	mux.HandleFunc("/jwks", m.jwks)
+
+	m.Server = httptest.NewServer(mux)
+	t.Cleanup(m.Close)
+
+	return m
+}
+
+func (m *mockOIDCServer) discovery(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(oidcDiscovery{
+		Issuer:                m.URL,
+		AuthorizationEndpoint: m.URL + "/authorize",
+		TokenEndpoint:         m.URL + "/token",
+		JWKSURI:               m.URL + "/jwks",
+	})
+}
+
+func (m *mockOIDCServer) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	code, _ := utils.GenerateRandomToken(8)
+
+	m.mu.Lock()
+	m.codes[code] = query
+	m.mu.Unlock()
+
+	http.Redirect(w, r, query.Get("redirect_uri")+"?"+url.Values{"code": {code}, "state": {query.Get("state")}}.Encode(), http.StatusFound)
+}
+
+func (m *mockOIDCServer) token(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+
+	m.mu.Lock()
+	authorization, ok := m.codes[r.PostForm.Get("code")]
+	delete(m.codes, r.PostForm.Get("code"))
+	m.mu.Unlock()
+
+	if !ok || r.PostForm.Get("client_secret") != testClientSecret ||
+		CodeChallenge(r.PostForm.Get("code_verifier")) != authorization.Get("code_challenge") {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            m.URL,
+		"aud":            authorization.Get("client_id"),
+		"sub":            testSubject,
+		"email":          "jane@example.com",
+		"email_verified": true,
+		"name":           "Jane",
+		"nonce":          authorization.Get("nonce"),
+		"exp":            time.Now().Add(time.Minute).Unix(),
+	})
+	token.Header["kid"] = "mock"
+
+	idToken, err := token.SignedString(m.key)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]string{"id_token": idToken})
+}
+
+func (m *mockOIDCServer) jwks(w http.ResponseWriter, r *http.Request) {
+	json.NewEncoder(w).Encode(utils.JWKS{Keys: []utils.JWK{{
+		KeyType:   "RSA",
+		KeyID:     "mock",
+		Use:       "sig",
+		Algorithm: "RS256",
+		N:         base64.RawURLEncoding.EncodeToString(m.key.N.Bytes()),
+		E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(m.key.E)).Bytes()),
+	}}})
+}
+
+// authorizeCode follows the authorization URL to the provider and returns the query of its redirect to the callback
+func authorizeCode(t *testing.T, authURL string) url.Values {
+	t.Helper()
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+
+	res, err := client.Get(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	location, err := url.Parse(res.Header.Get("Location"))
+	if err != nil || location.Query().Get("code") == "" {
+		t.Fatalf("expected a redirect with a code, got status %d to %q", res.StatusCode, res.Header.Get("Location"))
+	}
+
+	return location.Query()
+}
+
+func newTestOIDCProvider(issuer string) *OIDCProvider {
+	return NewOIDCProvider(config.OIDCProviderConfig{
+		Name:         "mock",
+		Type:         "oidc",
+		Issuer:       issuer,
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  testRedirectURL,
+	})
+}
+
+func TestOIDCProviderExchange(t *testing.T) {
+	server := newMockOIDCServer(t)
+	provider := newTestOIDCProvider(server.URL)
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		exchangeNonce string
+		wrongVerifier bool
+		wantErr       bool
+	}{
+		{name: "valid", exchangeNonce: "nonce"},
+		{name: "nonce mismatch", exchangeNonce: "other", wantErr: true},
+		{name: "wrong verifier", exchangeNonce: "nonce", wrongVerifier: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", CodeChallenge("verifier"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			callback := authorizeCode(t, authURL)
+			if callback.Get("state") != "state" {
+				t.Fatalf("expected the state to be returned, got %q", callback.Get("state"))
+			}
+
+			verifier := "verifier"
+			if tt.wrongVerifier {
+				verifier = "other"
+			}
+
+			claims, err := provider.Exchange(ctx, callback.Get("code"), verifier, tt.exchangeNonce)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected the exchange to fail")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if claims.Subject != testSubject || claims.Email != "jane@example.com" || !claims.EmailVerified {
+				t.Fatalf("unexpected claims %+v", claims)
+			}
+		})
+	}
+}
+
+// fakeAuthRepo keeps the social login state in memory, the other methods are not used by the tests
+type fakeAuthRepo struct {
+	Repository
+
+	states     map[string]*OAuthState
+	loginCodes map[string]*OAuthLoginCode
+}
+
+func (r *fakeAuthRepo) SaveOAuthState(ctx context.Context, state *OAuthState) error {
+	r.states[state.State] = state
+	return nil
+}
+
+func (r *fakeAuthRepo) ConsumeOAuthState(ctx context.Context, state string) (*OAuthState, error) {
+	oauthState, ok := r.states[state]
+	if !ok {
+		return nil, ErrInvalidOAuthState
+	}
+	delete(r.states, state)
+
+	return oauthState, nil
+}
+
+func (r *fakeAuthRepo) SaveOAuthLoginCode(ctx context.Context, loginCode *OAuthLoginCode) error {
+	r.loginCodes[loginCode.CodeHash] = loginCode
+	return nil
+}
+
+func (r *fakeAuthRepo) GetIdentity(ctx context.Context, provider, subject string) (*Identity, error) {
+	return &Identity{ID: 1, UserID: 7, Provider: provider, Subject: subject}, nil
+}
+
+// fakeUserRepo returns the user linked to every identity
+type fakeUserRepo struct {
+	user.Repository
+}
+
+func (r *fakeUserRepo) GetByID(ctx context.Context, id int) (*user.User, error) {
+	return &user.User{ID: int64(id), Email: "jane@example.com"}, nil
+}
+
+func TestOIDCCallbackRequiresStateCookie(t *testing.T) {
+	config.AppConfig = &config.Config{AppEnv: "development", AppURL: "http://app.test", DBTimeout: 2}
+
+	server := newMockOIDCServer(t)
+	authRepo := &fakeAuthRepo{states: make(map[string]*OAuthState), loginCodes: make(map[string]*OAuthLoginCode)}
+	providers := map[string]SocialProvider{"mock": newTestOIDCProvider(server.URL)}
+	handler := NewHandler(NewService(&fakeUserRepo{}, authRepo, nil, nil, nil, nil, providers))
+
+	r := chi.NewRouter()
+	r.Get("/auth/oidc/{provider}/login", handler.OIDCLogin)
+	r.Get("/auth/oidc/{provider}/callback", handler.OIDCCallback)
+
+	// login starts the flow and returns the redirect to the provider and the state cookie
+	login := func(t *testing.T) (string, *http.Cookie) {
+		t.Helper()
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/oidc/mock/login", nil))
+		if w.Code != http.StatusFound {
+			t.Fatalf("expected a redirect to the provider, got %d", w.Code)
+		}
+
+		cookies := w.Result().Cookies()
+		if len(cookies) != 1 || cookies[0].Name != oauthStateCookie || !cookies[0].HttpOnly || cookies[0].SameSite != http.SameSiteLaxMode {
+			t.Fatalf("expected an http only lax state cookie, got %+v", cookies)
+		}
+		if cookies[0].Path != "/auth/oidc/mock" {
+			t.Fatalf("expected the cookie scoped to the provider, got path %q", cookies[0].Path)
+		}
+
+		return w.Header().Get("Location"), cookies[0]
+	}
+
+	// callback redirects back from the provider, returning the fragment of the redirect to the frontend
+	callback := func(t *testing.T, query url.Values, cookie *http.Cookie) url.Values {
+		t.Helper()
+
+		req := httptest.NewRequest(http.MethodGet, "/auth/oidc/mock/callback?"+query.Encode(), nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		location, err := url.Parse(w.Header().Get("Location"))
+		if w.Code != http.StatusFound || err != nil || !strings.HasPrefix(location.String(), "http://app.test/oauth/callback#") {
+			t.Fatalf("expected a redirect to the frontend, got %d to %q", w.Code, w.Header().Get("Location"))
+		}
+
+		fragment, err := url.ParseQuery(location.Fragment)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return fragment
+	}
+
+	t.Run("without the cookie", func(t *testing.T) {
+		authURL, _ := login(t)
+
+		result := callback(t, authorizeCode(t, authURL), nil)
+		if result.Get("error") != ErrInvalidOAuthState.Code || result.Get("code") != "" {
+			t.Fatalf("expected the invalid state error, got %v", result)
+		}
+	})
+
+	t.Run("with the cookie of another login", func(t *testing.T) {
+		authURL, _ := login(t)
+		_, otherCookie := login(t)
+
+		result := callback(t, authorizeCode(t, authURL), otherCookie)
+		if result.Get("error") != ErrInvalidOAuthState.Code {
+			t.Fatalf("expected the invalid state error, got %v", result)
+		}
+	})
+
+	t.Run("with the cookie", func(t *testing.T) {
+		authURL, cookie := login(t)
+
+		result := callback(t, authorizeCode(t, authURL), cookie)
+		code := result.Get("code")
+		if code == "" {
+			t.Fatalf("expected a login code, got %v", result)
+		}
+
+		loginCode, ok := authRepo.loginCodes[utils.HashToken(code)]
+		if !ok || loginCode.UserID != 7 {
+			t.Fatalf("expected the login code of the linked user to be stored, got %+v", loginCode)
+		}
+	})
+}
+
+func TestCompleteOIDCLoginRejectsReplayedState(t *testing.T) {
+	config.AppConfig = &config.Config{DBTimeout: 2}
+
+	authRepo := &fakeAuthRepo{states: map[string]*OAuthState{
+		"state": {State: "state", Provider: "mock", CodeVerifier: "verifier", Nonce: "nonce", ExpiresAt: time.Now().Add(time.Minute)},
+	}}
+	providers := map[string]SocialProvider{"mock": newTestOIDCProvider("http://unused.test")}
+	s := NewService(&fakeUserRepo{}, authRepo, nil, nil, nil, nil, providers)
+
+	_, err := s.CompleteOIDCLogin(context.Background(), &OIDCCallbackReq{Provider: "mock", Code: "code", State: "state"})
+	if !errors.Is(err, ErrInvalidOAuthState) {
+		t.Fatalf("expected the invalid state error, got %v", err)
+	}
+
+	// The state of the browser's login is kept for its own callback
+	if _, ok := authRepo.states["state"]; !ok {
+		t.Fatal("expected the state to be kept")
+	}
+}
+
+// unlinkedAuthRepo has no identity linked yet and records the saved ones
+type unlinkedAuthRepo struct {
+	Repository
+
+	identities []*Identity
+}
+
+func (r *unlinkedAuthRepo) GetIdentity(ctx context.Context, provider, subject string) (*Identity, error) {
+	return nil, sql.ErrNoRows
+}
+
+func (r *unlinkedAuthRepo) SaveIdentity(ctx context.Context, identity *Identity) (*Identity, error) {
+	r.identities = append(r.identities, identity)
+	return identity, nil
+}
+
+// emailUserRepo holds a single local account found by its email
+type emailUserRepo struct {
+	user.Repository
+
+	user *user.User
+}
+
+func (r *emailUserRepo) GetByEmail(ctx context.Context, email string) (*user.User, error) {
+	return r.user, nil
+}
+
+func (r *emailUserRepo) GetByID(ctx context.Context, id int) (*user.User, error) {
+	return r.user, nil
+}
+
+func (r *emailUserRepo) MarkEmailVerified(ctx context.Context, userID int, email string) error {
+	return nil
+}
+
+func TestGetOrCreateOIDCUserLinksVerifiedAccounts(t *testing.T) {
+	claims := &OIDCClaims{Subject: testSubject, Email: "jane@example.com", EmailVerified: true}
+
+	t.Run("unverified account", func(t *testing.T) {
+		authRepo := &unlinkedAuthRepo{}
+		s := &service{authRepo: authRepo, userRepo: &emailUserRepo{user: &user.User{ID: 7, Email: claims.Email}}}
+
+		_, err := s.getOrCreateOIDCUser(context.Background(), "mock", claims)
+		if !errors.Is(err, ErrAccountLinkRequired) {
+			t.Fatalf("expected the account link required error, got %v", err)
+		}
+		if len(authRepo.identities) != 0 {
+			t.Fatal("expected the identity not to be linked")
+		}
+	})
+
+	t.Run("verified account", func(t *testing.T) {
+		verifiedAt := time.Now()
+		authRepo := &unlinkedAuthRepo{}
+		s := &service{authRepo: authRepo, userRepo: &emailUserRepo{user: &user.User{ID: 7, Email: claims.Email, EmailVerifiedAt: &verifiedAt}}}
+
+		u, err := s.getOrCreateOIDCUser(context.Background(), "mock", claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.ID != 7 || len(authRepo.identities) != 1 || authRepo.identities[0].UserID != 7 {
+			t.Fatalf("expected the identity to be linked to the account, got %+v", authRepo.identities)
+		}
+	})
+}
+
+// TestOIDCProviderMockOAuth2Server runs the flow against the mock-oauth2-server of docker-compose, set
+// MOCK_OIDC_ISSUER=http://localhost:8081/default to run it
+func TestOIDCProviderMockOAuth2Server(t *testing.T) {
+	issuer := os.Getenv("MOCK_OIDC_ISSUER")
+	if issuer == "" {
+		t.Skip("MOCK_OIDC_ISSUER is not set")
+	}
+
+	provider := newTestOIDCProvider(issuer)
+	ctx := context.Background()
+
+	authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", CodeChallenge("verifier-of-at-least-43-characters-for-pkce"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callback := authorizeCode(t, authURL)
+
+	claims, err := provider.Exchange(ctx, callback.Get("code"), "verifier-of-at-least-43-characters-for-pkce", "nonce")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Subject == "" {
+		t.Fatal("expected the subject of the signed in account")
+	}
+}
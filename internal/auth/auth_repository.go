@@ -3,6 +3,8 @@ package auth
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"time"
 )
 
 // Repository interface for auth repository
@@ -10,11 +12,103 @@ type Repository interface {
 	// Save stores a new refresh token in the data store.
 	Save(ctx context.Context, refreshToken *RefreshToken) (*RefreshToken, error)
 
-	// Delete removes a refresh token from the data store.
-	Delete(ctx context.Context, refreshTokenID int) error
+	// Rotate revokes the current refresh token and stores the new one of the same family.
+	Rotate(ctx context.Context, current *RefreshToken, next *RefreshToken) error
 
-	// FindByToken retrieves a refresh token by its token string from the data store.
+	// RevokeFamily revokes every active refresh token of the user's token family.
+	RevokeFamily(ctx context.Context, userID int, familyID string) error
+
+	// RevokeAllByUserID revokes every active refresh token of the user.
+	RevokeAllByUserID(ctx context.Context, userID int) error
+
+	// GetActiveByUserID retrieves the unexpired and unrevoked refresh tokens of the user, newest first.
+	GetActiveByUserID(ctx context.Context, userID int) (*[]RefreshToken, error)
+
+	// FindByToken retrieves an unexpired refresh token, including revoked ones, by its token string from the data store.
 	FindByToken(ctx context.Context, token string) (*RefreshToken, error)
+
+	// SavePasswordResetToken stores a new password reset token in the data store.
+	SavePasswordResetToken(ctx context.Context, resetToken *PasswordResetToken) (*PasswordResetToken, error)
+
+	// GetLatestPasswordResetToken retrieves the most recently sent password reset token of the user.
+	GetLatestPasswordResetToken(ctx context.Context, userID int) (*PasswordResetToken, error)
+
+	// CountPasswordResetTokensSince counts the password reset tokens of the user sent since the given time.
+	CountPasswordResetTokensSince(ctx context.Context, userID int, since time.Time) (int, error)
+
+	// ResetPassword consumes the unused and unexpired reset token of the hash along with every other outstanding
+	// token of the user, sets the hashed password and revokes the sessions of the user in one transaction, and
+	// returns the user id of the token.
+	ResetPassword(ctx context.Context, tokenHash string, password string) (int64, error)
+
+	// SaveEmailChangeToken stores a new email change token in the data store.
+	SaveEmailChangeToken(ctx context.Context, changeToken *EmailChangeToken) (*EmailChangeToken, error)
+
+	// ConsumeEmailChangeToken marks the unused and unexpired email change token of the hash as used along with every
+	// other outstanding token of the user, and returns the token.
+	ConsumeEmailChangeToken(ctx context.Context, tokenHash string) (*EmailChangeToken, error)
+
+	// SaveVerificationCode stores a new verification code in the data store.
+	SaveVerificationCode(ctx context.Context, code *VerificationCode) (*VerificationCode, error)
+
+	// GetLatestVerificationCode retrieves the most recently sent verification code of the user's channel.
+	GetLatestVerificationCode(ctx context.Context, userID int, channel string) (*VerificationCode, error)
+
+	// CountVerificationCodesSince counts the verification codes of the user's channel sent since the given time.
+	CountVerificationCodesSince(ctx context.Context, userID int, channel string, since time.Time) (int, error)
+
+	// RecordVerificationAttempt counts an attempt against the code, false is returned once maxAttempts is reached.
+	RecordVerificationAttempt(ctx context.Context, codeID int64, maxAttempts int) (bool, error)
+
+	// ConsumeVerificationCode marks the unconsumed code as used, ErrInvalidVerificationCode is returned if it already was.
+	ConsumeVerificationCode(ctx context.Context, codeID int64) error
+
+	// RecordLoginAttempt stores the audit record of a failed login.
+	RecordLoginAttempt(ctx context.Context, attempt *LoginAttempt) error
+
+	// CountFailedLoginsByIP counts the failed logins from the IP address since the given time.
+	CountFailedLoginsByIP(ctx context.Context, ipAddress string, since time.Time) (int, error)
+
+	// GetMFA retrieves the TOTP enrollment of the user.
+	GetMFA(ctx context.Context, userID int) (*UserMFA, error)
+
+	// SaveMFASecret stores a pending TOTP secret for the user, ErrMFAAlreadyEnabled is returned once enabled.
+	SaveMFASecret(ctx context.Context, userID int, secret string) error
+
+	// EnableMFA confirms the pending enrollment of the user and replaces the recovery codes with the given hashes.
+	EnableMFA(ctx context.Context, userID int, step int64, recoveryCodeHashes []string) error
+
+	// DisableMFA removes the TOTP enrollment and the recovery codes of the user.
+	DisableMFA(ctx context.Context, userID int) error
+
+	// UseTOTPStep records the time step of an accepted code, false is returned if the step was already used.
+	UseTOTPStep(ctx context.Context, userID int, step int64) (bool, error)
+
+	// UseRecoveryCode marks the unused recovery code of the hash as used, false is returned if there is none.
+	UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error)
+
+	// SaveOAuthState stores a pending social login state.
+	SaveOAuthState(ctx context.Context, state *OAuthState) error
+
+	// ConsumeOAuthState removes and returns the unexpired state, ErrInvalidOAuthState is returned if there is none.
+	ConsumeOAuthState(ctx context.Context, state string) (*OAuthState, error)
+
+	// SaveOAuthLoginCode stores the code of a completed social login.
+	SaveOAuthLoginCode(ctx context.Context, loginCode *OAuthLoginCode) error
+
+	// ConsumeOAuthLoginCode removes and returns the unexpired login code of the hash, ErrInvalidLoginCode is returned if there is none.
+	ConsumeOAuthLoginCode(ctx context.Context, codeHash string) (*OAuthLoginCode, error)
+
+	// GetIdentity retrieves the linked identity of the provider's subject.
+	GetIdentity(ctx context.Context, provider, subject string) (*Identity, error)
+
+	// SaveIdentity links the provider identity to the user.
+	SaveIdentity(ctx context.Context, identity *Identity) (*Identity, error)
+}
+
+// rowQuerier is satisfied by both *sql.DB and *sql.Tx
+type rowQuerier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
 }
 
 type repository struct {
@@ -29,46 +123,670 @@ func NewRepository(db *sql.DB) Repository {
 }
 
 func (r *repository) Save(ctx context.Context, refreshToken *RefreshToken) (*RefreshToken, error) {
-	var refreshTokenID int
-	insertQuery := `INSERT INTO refresh_tokens(user_id, token, expires_at) VALUES ($1, $2, $3) RETURNING id`
+	return save(ctx, r.db, refreshToken)
+}
 
-	err := r.db.QueryRowContext(ctx, insertQuery,
+// save stores the refresh token using either the database or a transaction
+func save(ctx context.Context, q rowQuerier, refreshToken *RefreshToken) (*RefreshToken, error) {
+	insertQuery := `INSERT INTO refresh_tokens(user_id, token, family_id, user_agent, ip_address, expires_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`
+
+	err := q.QueryRowContext(ctx, insertQuery,
 		refreshToken.UserID,
 		refreshToken.Token,
+		refreshToken.FamilyID,
+		refreshToken.UserAgent,
+		refreshToken.IPAddress,
 		refreshToken.ExpiresAt,
-	).Scan(&refreshTokenID)
+	).Scan(
+		&refreshToken.ID,
+		&refreshToken.CreatedAt,
+	)
 
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken.ID = int64(refreshTokenID)
-
 	return refreshToken, nil
 }
 
-func (r *repository) Delete(ctx context.Context, refreshTokenID int) error {
-	deleteQuery := `DELETE FROM refresh_tokens WHERE id = $1`
+func (r *repository) Rotate(ctx context.Context, current *RefreshToken, next *RefreshToken) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// Rollback is a no-op once the transaction is committed
+	defer tx.Rollback()
 
-	_, err := r.db.ExecContext(ctx, deleteQuery, refreshTokenID)
+	// Only one rotation can win when the same token is presented concurrently
+	revokeQuery := `UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE id = $1 AND revoked_at IS NULL`
+	result, err := tx.ExecContext(ctx, revokeQuery, current.ID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrRefreshTokenReused
+	}
+
+	if _, err := save(ctx, tx, next); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *repository) RevokeFamily(ctx context.Context, userID int, familyID string) error {
+	revokeQuery := `UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND family_id = $2 AND revoked_at IS NULL`
+
+	_, err := r.db.ExecContext(ctx, revokeQuery, userID, familyID)
 
 	return err
 }
 
+func (r *repository) RevokeAllByUserID(ctx context.Context, userID int) error {
+	revokeQuery := `UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND revoked_at IS NULL`
+
+	_, err := r.db.ExecContext(ctx, revokeQuery, userID)
+
+	return err
+}
+
+func (r *repository) GetActiveByUserID(ctx context.Context, userID int) (*[]RefreshToken, error) {
+	selectQueryByUserID := `SELECT id, user_id, family_id, COALESCE(user_agent, ''), COALESCE(ip_address, ''), expires_at, created_at FROM refresh_tokens WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, selectQueryByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	refreshTokens := []RefreshToken{}
+	for rows.Next() {
+		var refreshToken RefreshToken
+		if err := rows.Scan(
+			&refreshToken.ID,
+			&refreshToken.UserID,
+			&refreshToken.FamilyID,
+			&refreshToken.UserAgent,
+			&refreshToken.IPAddress,
+			&refreshToken.ExpiresAt,
+			&refreshToken.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		refreshTokens = append(refreshTokens, refreshToken)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &refreshTokens, nil
+}
+
 func (r *repository) FindByToken(ctx context.Context, token string) (*RefreshToken, error) {
 	var refreshToken RefreshToken
-	selectQueryByID := `SELECT * FROM refresh_tokens WHERE token = $1 AND expires_at > CURRENT_TIMESTAMP`
+	var revokedAt sql.NullTime
+	selectQueryByToken := `SELECT id, user_id, token, family_id, COALESCE(user_agent, ''), COALESCE(ip_address, ''), expires_at, revoked_at, created_at FROM refresh_tokens WHERE token = $1 AND expires_at > CURRENT_TIMESTAMP`
 
-	err := r.db.QueryRowContext(ctx, selectQueryByID, token).Scan(
+	err := r.db.QueryRowContext(ctx, selectQueryByToken, token).Scan(
 		&refreshToken.ID,
 		&refreshToken.UserID,
 		&refreshToken.Token,
+		&refreshToken.FamilyID,
+		&refreshToken.UserAgent,
+		&refreshToken.IPAddress,
 		&refreshToken.ExpiresAt,
+		&revokedAt,
+		&refreshToken.CreatedAt,
 	)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if revokedAt.Valid {
+		refreshToken.RevokedAt = &revokedAt.Time
+	}
+
 	return &refreshToken, nil
 }
+
+func (r *repository) SavePasswordResetToken(ctx context.Context, resetToken *PasswordResetToken) (*PasswordResetToken, error) {
+	insertQuery := `INSERT INTO password_reset_tokens(user_id, token_hash, expires_at) VALUES ($1, $2, $3) RETURNING id, created_at`
+
+	err := r.db.QueryRowContext(ctx, insertQuery,
+		resetToken.UserID,
+		resetToken.TokenHash,
+		resetToken.ExpiresAt,
+	).Scan(
+		&resetToken.ID,
+		&resetToken.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return resetToken, nil
+}
+
+func (r *repository) GetLatestPasswordResetToken(ctx context.Context, userID int) (*PasswordResetToken, error) {
+	var resetToken PasswordResetToken
+	var usedAt sql.NullTime
+	selectQuery := `SELECT id, user_id, token_hash, expires_at, used_at, created_at FROM password_reset_tokens WHERE user_id = $1 ORDER BY created_at DESC, id DESC LIMIT 1`
+
+	err := r.db.QueryRowContext(ctx, selectQuery, userID).Scan(
+		&resetToken.ID,
+		&resetToken.UserID,
+		&resetToken.TokenHash,
+		&resetToken.ExpiresAt,
+		&usedAt,
+		&resetToken.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if usedAt.Valid {
+		resetToken.UsedAt = &usedAt.Time
+	}
+
+	return &resetToken, nil
+}
+
+func (r *repository) CountPasswordResetTokensSince(ctx context.Context, userID int, since time.Time) (int, error) {
+	var count int
+	countQuery := `SELECT COUNT(*) FROM password_reset_tokens WHERE user_id = $1 AND created_at >= $2`
+
+	err := r.db.QueryRowContext(ctx, countQuery, userID, since).Scan(&count)
+
+	return count, err
+}
+
+func (r *repository) ResetPassword(ctx context.Context, tokenHash string, password string) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	// Rollback is a no-op once the transaction is committed
+	defer tx.Rollback()
+
+	// Only one request can consume the token when it is presented concurrently
+	var userID int64
+	consumeQuery := `UPDATE password_reset_tokens SET used_at = CURRENT_TIMESTAMP WHERE token_hash = $1 AND used_at IS NULL AND expires_at > CURRENT_TIMESTAMP RETURNING user_id`
+	err = tx.QueryRowContext(ctx, consumeQuery, tokenHash).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrInvalidResetToken
+		}
+		return 0, err
+	}
+
+	// The other links mailed to the user are no longer needed once the password is reset
+	invalidateQuery := `UPDATE password_reset_tokens SET used_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND used_at IS NULL`
+	if _, err := tx.ExecContext(ctx, invalidateQuery, userID); err != nil {
+		return 0, err
+	}
+
+	// The token stays unused when the password can't be updated
+	passwordUpdateQuery := `UPDATE users SET password = $1 WHERE id = $2`
+	if _, err := tx.ExecContext(ctx, passwordUpdateQuery, password, userID); err != nil {
+		return 0, err
+	}
+
+	// Whoever knew the old password must not keep a session
+	revokeQuery := `UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND revoked_at IS NULL`
+	if _, err := tx.ExecContext(ctx, revokeQuery, userID); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return userID, nil
+}
+
+func (r *repository) SaveEmailChangeToken(ctx context.Context, changeToken *EmailChangeToken) (*EmailChangeToken, error) {
+	insertQuery := `INSERT INTO email_change_tokens(user_id, new_email, token_hash, expires_at) VALUES ($1, $2, $3, $4) RETURNING id, created_at`
+
+	err := r.db.QueryRowContext(ctx, insertQuery,
+		changeToken.UserID,
+		changeToken.NewEmail,
+		changeToken.TokenHash,
+		changeToken.ExpiresAt,
+	).Scan(
+		&changeToken.ID,
+		&changeToken.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return changeToken, nil
+}
+
+func (r *repository) ConsumeEmailChangeToken(ctx context.Context, tokenHash string) (*EmailChangeToken, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Rollback is a no-op once the transaction is committed
+	defer tx.Rollback()
+
+	// Only one request can consume the token when it is presented concurrently
+	var changeToken EmailChangeToken
+	consumeQuery := `UPDATE email_change_tokens SET used_at = CURRENT_TIMESTAMP
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > CURRENT_TIMESTAMP
+		RETURNING id, user_id, new_email, expires_at, used_at, created_at`
+	err = tx.QueryRowContext(ctx, consumeQuery, tokenHash).Scan(
+		&changeToken.ID,
+		&changeToken.UserID,
+		&changeToken.NewEmail,
+		&changeToken.ExpiresAt,
+		&changeToken.UsedAt,
+		&changeToken.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidEmailChangeToken
+		}
+		return nil, err
+	}
+
+	// Links for other addresses requested earlier are void once one of them is confirmed
+	invalidateQuery := `UPDATE email_change_tokens SET used_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND used_at IS NULL`
+	if _, err := tx.ExecContext(ctx, invalidateQuery, changeToken.UserID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &changeToken, nil
+}
+
+func (r *repository) SaveVerificationCode(ctx context.Context, code *VerificationCode) (*VerificationCode, error) {
+	insertQuery := `INSERT INTO verification_codes(user_id, channel, target, code_hash, expires_at) VALUES ($1, $2, $3, $4, $5) RETURNING id, attempts, created_at`
+
+	err := r.db.QueryRowContext(ctx, insertQuery,
+		code.UserID,
+		code.Channel,
+		code.Target,
+		code.CodeHash,
+		code.ExpiresAt,
+	).Scan(
+		&code.ID,
+		&code.Attempts,
+		&code.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return code, nil
+}
+
+func (r *repository) GetLatestVerificationCode(ctx context.Context, userID int, channel string) (*VerificationCode, error) {
+	var code VerificationCode
+	var consumedAt sql.NullTime
+	selectQuery := `SELECT id, user_id, channel, target, code_hash, attempts, expires_at, consumed_at, created_at FROM verification_codes WHERE user_id = $1 AND channel = $2 ORDER BY created_at DESC, id DESC LIMIT 1`
+
+	err := r.db.QueryRowContext(ctx, selectQuery, userID, channel).Scan(
+		&code.ID,
+		&code.UserID,
+		&code.Channel,
+		&code.Target,
+		&code.CodeHash,
+		&code.Attempts,
+		&code.ExpiresAt,
+		&consumedAt,
+		&code.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if consumedAt.Valid {
+		code.ConsumedAt = &consumedAt.Time
+	}
+
+	return &code, nil
+}
+
+func (r *repository) CountVerificationCodesSince(ctx context.Context, userID int, channel string, since time.Time) (int, error) {
+	var count int
+	countQuery := `SELECT COUNT(*) FROM verification_codes WHERE user_id = $1 AND channel = $2 AND created_at >= $3`
+
+	err := r.db.QueryRowContext(ctx, countQuery, userID, channel, since).Scan(&count)
+
+	return count, err
+}
+
+func (r *repository) RecordVerificationAttempt(ctx context.Context, codeID int64, maxAttempts int) (bool, error) {
+	// Counting in the update keeps concurrent guesses within the limit
+	attemptQuery := `UPDATE verification_codes SET attempts = attempts + 1 WHERE id = $1 AND attempts < $2`
+
+	result, err := r.db.ExecContext(ctx, attemptQuery, codeID, maxAttempts)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (r *repository) ConsumeVerificationCode(ctx context.Context, codeID int64) error {
+	consumeQuery := `UPDATE verification_codes SET consumed_at = CURRENT_TIMESTAMP WHERE id = $1 AND consumed_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, consumeQuery, codeID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrInvalidVerificationCode
+	}
+
+	return nil
+}
+
+func (r *repository) RecordLoginAttempt(ctx context.Context, attempt *LoginAttempt) error {
+	insertQuery := `INSERT INTO login_attempts(user_id, email, ip_address, user_agent, reason) VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
+
+	return r.db.QueryRowContext(ctx, insertQuery,
+		attempt.UserID,
+		attempt.Email,
+		attempt.IPAddress,
+		attempt.UserAgent,
+		attempt.Reason,
+	).Scan(
+		&attempt.ID,
+		&attempt.CreatedAt,
+	)
+}
+
+func (r *repository) CountFailedLoginsByIP(ctx context.Context, ipAddress string, since time.Time) (int, error) {
+	var count int
+	countQuery := `SELECT COUNT(*) FROM login_attempts WHERE ip_address = $1 AND created_at >= $2`
+
+	err := r.db.QueryRowContext(ctx, countQuery, ipAddress, since).Scan(&count)
+
+	return count, err
+}
+
+func (r *repository) GetMFA(ctx context.Context, userID int) (*UserMFA, error) {
+	var mfa UserMFA
+	var enabledAt sql.NullTime
+	selectQuery := `SELECT user_id, secret, enabled_at, last_used_step, created_at FROM user_mfa WHERE user_id = $1`
+
+	err := r.db.QueryRowContext(ctx, selectQuery, userID).Scan(
+		&mfa.UserID,
+		&mfa.Secret,
+		&enabledAt,
+		&mfa.LastUsedStep,
+		&mfa.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if enabledAt.Valid {
+		mfa.EnabledAt = &enabledAt.Time
+	}
+
+	return &mfa, nil
+}
+
+func (r *repository) SaveMFASecret(ctx context.Context, userID int, secret string) error {
+	// A pending enrollment is replaced, an enabled one is kept
+	upsertQuery := `INSERT INTO user_mfa(user_id, secret) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET secret = EXCLUDED.secret, last_used_step = 0, created_at = CURRENT_TIMESTAMP
+		WHERE user_mfa.enabled_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, upsertQuery, userID, secret)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrMFAAlreadyEnabled
+	}
+
+	return nil
+}
+
+func (r *repository) EnableMFA(ctx context.Context, userID int, step int64, recoveryCodeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// Rollback is a no-op once the transaction is committed
+	defer tx.Rollback()
+
+	enableQuery := `UPDATE user_mfa SET enabled_at = CURRENT_TIMESTAMP, last_used_step = $1 WHERE user_id = $2 AND enabled_at IS NULL`
+	result, err := tx.ExecContext(ctx, enableQuery, step, userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrMFAAlreadyEnabled
+	}
+
+	deleteQuery := `DELETE FROM mfa_recovery_codes WHERE user_id = $1`
+	if _, err := tx.ExecContext(ctx, deleteQuery, userID); err != nil {
+		return err
+	}
+
+	insertQuery := `INSERT INTO mfa_recovery_codes(user_id, code_hash) VALUES ($1, $2)`
+	for _, codeHash := range recoveryCodeHashes {
+		if _, err := tx.ExecContext(ctx, insertQuery, userID, codeHash); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *repository) DisableMFA(ctx context.Context, userID int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// Rollback is a no-op once the transaction is committed
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_mfa WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *repository) UseTOTPStep(ctx context.Context, userID int, step int64) (bool, error) {
+	// Moving the step forward only once rejects a replayed code
+	useQuery := `UPDATE user_mfa SET last_used_step = $1 WHERE user_id = $2 AND last_used_step < $1`
+
+	result, err := r.db.ExecContext(ctx, useQuery, step, userID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (r *repository) UseRecoveryCode(ctx context.Context, userID int, codeHash string) (bool, error) {
+	useQuery := `UPDATE mfa_recovery_codes SET used_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, useQuery, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (r *repository) SaveOAuthState(ctx context.Context, state *OAuthState) error {
+	// Abandoned logins are cleaned up as new ones start
+	deleteQuery := `DELETE FROM oauth_states WHERE expires_at <= CURRENT_TIMESTAMP`
+	if _, err := r.db.ExecContext(ctx, deleteQuery); err != nil {
+		return err
+	}
+
+	insertQuery := `INSERT INTO oauth_states(state, provider, code_verifier, nonce, expires_at) VALUES ($1, $2, $3, $4, $5)`
+
+	_, err := r.db.ExecContext(ctx, insertQuery,
+		state.State,
+		state.Provider,
+		state.CodeVerifier,
+		state.Nonce,
+		state.ExpiresAt,
+	)
+
+	return err
+}
+
+func (r *repository) ConsumeOAuthState(ctx context.Context, state string) (*OAuthState, error) {
+	var oauthState OAuthState
+	// Deleting the state makes every login state single use
+	consumeQuery := `DELETE FROM oauth_states WHERE state = $1 AND expires_at > CURRENT_TIMESTAMP RETURNING state, provider, code_verifier, nonce, expires_at`
+
+	err := r.db.QueryRowContext(ctx, consumeQuery, state).Scan(
+		&oauthState.State,
+		&oauthState.Provider,
+		&oauthState.CodeVerifier,
+		&oauthState.Nonce,
+		&oauthState.ExpiresAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidOAuthState
+		}
+		return nil, err
+	}
+
+	return &oauthState, nil
+}
+
+func (r *repository) SaveOAuthLoginCode(ctx context.Context, loginCode *OAuthLoginCode) error {
+	// Codes the frontend never exchanged are cleaned up as new ones are issued
+	deleteQuery := `DELETE FROM oauth_login_codes WHERE expires_at <= CURRENT_TIMESTAMP`
+	if _, err := r.db.ExecContext(ctx, deleteQuery); err != nil {
+		return err
+	}
+
+	insertQuery := `INSERT INTO oauth_login_codes(code_hash, user_id, expires_at) VALUES ($1, $2, $3)`
+
+	_, err := r.db.ExecContext(ctx, insertQuery,
+		loginCode.CodeHash,
+		loginCode.UserID,
+		loginCode.ExpiresAt,
+	)
+
+	return err
+}
+
+func (r *repository) ConsumeOAuthLoginCode(ctx context.Context, codeHash string) (*OAuthLoginCode, error) {
+	var loginCode OAuthLoginCode
+	// Deleting the code makes it single use
+	consumeQuery := `DELETE FROM oauth_login_codes WHERE code_hash = $1 AND expires_at > CURRENT_TIMESTAMP RETURNING code_hash, user_id, expires_at`
+
+	err := r.db.QueryRowContext(ctx, consumeQuery, codeHash).Scan(
+		&loginCode.CodeHash,
+		&loginCode.UserID,
+		&loginCode.ExpiresAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidLoginCode
+		}
+		return nil, err
+	}
+
+	return &loginCode, nil
+}
+
+func (r *repository) GetIdentity(ctx context.Context, provider, subject string) (*Identity, error) {
+	var identity Identity
+	selectQuery := `SELECT id, user_id, provider, subject, COALESCE(email, ''), created_at FROM user_identities WHERE provider = $1 AND subject = $2`
+
+	err := r.db.QueryRowContext(ctx, selectQuery, provider, subject).Scan(
+		&identity.ID,
+		&identity.UserID,
+		&identity.Provider,
+		&identity.Subject,
+		&identity.Email,
+		&identity.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &identity, nil
+}
+
+func (r *repository) SaveIdentity(ctx context.Context, identity *Identity) (*Identity, error) {
+	insertQuery := `INSERT INTO user_identities(user_id, provider, subject, email) VALUES ($1, $2, $3, NULLIF($4, '')) RETURNING id, created_at`
+
+	err := r.db.QueryRowContext(ctx, insertQuery,
+		identity.UserID,
+		identity.Provider,
+		identity.Subject,
+		identity.Email,
+	).Scan(
+		&identity.ID,
+		&identity.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return identity, nil
+}
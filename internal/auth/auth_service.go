@@ -2,14 +2,45 @@ package auth
 
 import (
 	"context"
+	"crypto/subtle"
+	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/aslam-ep/go-e-commerce/config"
+	"github.com/aslam-ep/go-e-commerce/internal/notification"
+	"github.com/aslam-ep/go-e-commerce/internal/revocation"
 	"github.com/aslam-ep/go-e-commerce/internal/user"
 	"github.com/aslam-ep/go-e-commerce/utils"
 )
 
+const (
+	accessTokenExpiry  = time.Minute * 15
+	refreshTokenExpiry = time.Hour * 24 * 7
+	resetTokenExpiry   = time.Minute * 30
+
+	resetResendInterval  = time.Minute
+	maxResetTokensPerDay = 5
+
+	emailChangeTokenExpiry = time.Hour * 24
+
+	verificationCodeExpiry     = time.Minute * 15
+	verificationResendInterval = time.Minute
+	maxVerificationAttempts    = 5
+	maxVerificationCodesPerDay = 10
+
+	loginIPWindow      = time.Minute * 15
+	maxLockoutDuration = time.Hour * 24
+
+	mfaTokenExpiry     = time.Minute * 5
+	recoveryCodesCount = 10
+
+	oauthStateExpiry     = time.Minute * 10
+	oauthLoginCodeExpiry = time.Minute
+)
+
 // Service interface defines the methods required for authentication services.
 type Service interface {
 	// RegisterUser Creates a new user based on the provided request and returns the created user's details.
@@ -18,25 +49,112 @@ type Service interface {
 	// Authenticate checks the provided login credentials and returns a login response.
 	Authenticate(ctx context.Context, req *LoginReq) (*LoginRes, error)
 
-	// RefreshToken verifies the provided refresh token and issues a new access token.
+	// RefreshToken verifies the provided refresh token, rotates it and issues a new access token.
 	RefreshToken(ctx context.Context, req *RefreshTokenReq) (*RefreshTokenRes, error)
+
+	// Logout revokes the session of the provided refresh token.
+	Logout(c context.Context, req *LogoutReq) (*utils.MessageRes, error)
+
+	// LogoutAll revokes every session of the given user.
+	LogoutAll(c context.Context, userID int) (*utils.MessageRes, error)
+
+	// ListSessions returns the active sessions of the given user.
+	ListSessions(c context.Context, userID int) (*ListSessionRes, error)
+
+	// VerifyMFA completes the login of the MFA token with an authenticator or recovery code.
+	VerifyMFA(c context.Context, req *VerifyMFAReq) (*LoginRes, error)
+
+	// EnrollMFA generates a new TOTP secret for the user, enabled once confirmed.
+	EnrollMFA(c context.Context, userID int) (*MFAEnrollRes, error)
+
+	// ConfirmMFA enables two factor authentication with a code of the enrolled secret and returns the recovery codes.
+	ConfirmMFA(c context.Context, req *MFACodeReq) (*MFARecoveryCodesRes, error)
+
+	// DisableMFA disables two factor authentication with an authenticator or recovery code.
+	DisableMFA(c context.Context, req *MFACodeReq) (*utils.MessageRes, error)
+
+	// StartOIDCLogin starts a social login with the provider and returns its authorization URL along with the state
+	// to store in the browser.
+	StartOIDCLogin(c context.Context, provider string) (string, string, error)
+
+	// CompleteOIDCLogin signs in the user of the provider identity, linking or creating the account on first login,
+	// and returns the single use code the frontend exchanges for the tokens.
+	CompleteOIDCLogin(c context.Context, req *OIDCCallbackReq) (string, error)
+
+	// ExchangeOIDCLoginCode exchanges the code of a completed social login for a login response.
+	ExchangeOIDCLoginCode(c context.Context, req *OIDCExchangeReq) (*LoginRes, error)
+
+	// UnlockUser clears the failed logins and the lockout of the given user.
+	UnlockUser(c context.Context, userID int) (*utils.MessageRes, error)
+
+	// LockUser locks the given user out of login for the requested minutes and ends all of their sessions.
+	LockUser(c context.Context, req *LockUserReq) (*utils.MessageRes, error)
+
+	// ForceLogout ends every session of the given user.
+	ForceLogout(c context.Context, userID int) (*utils.MessageRes, error)
+
+	// GetJWKS returns the public keys for verifying the issued access tokens.
+	GetJWKS() *utils.JWKS
+
+	// ForgotPassword mails a password reset link to the user of the email, if there is one.
+	ForgotPassword(c context.Context, req *ForgotPasswordReq) (*utils.MessageRes, error)
+
+	// ResetPassword sets the new password of the reset token's user and ends all of their sessions.
+	ResetPassword(c context.Context, req *ResetPasswordReq) (*utils.MessageRes, error)
+
+	// RequestEmailChange mails a confirmation link to the new email and a notice to the current one.
+	RequestEmailChange(c context.Context, req *ChangeEmailReq) (*utils.MessageRes, error)
+
+	// ConfirmEmailChange replaces the user's email with the confirmed one and ends all of their sessions.
+	ConfirmEmailChange(c context.Context, req *ConfirmEmailChangeReq) (*utils.MessageRes, error)
+
+	// ConfirmVerification verifies the user's email or phone with the code sent to it.
+	ConfirmVerification(c context.Context, req *ConfirmVerificationReq) (*utils.MessageRes, error)
+
+	// ResendVerification sends a new verification code to the user's email or phone.
+	ResendVerification(c context.Context, req *ResendVerificationReq) (*utils.MessageRes, error)
 }
 
 type service struct {
-	userRepo user.Repository
-	authRepo Repository
-	timeout  time.Duration
-	secret   string
+	userRepo        user.Repository
+	authRepo        Repository
+	revocationStore revocation.Store
+	mailer          notification.Mailer
+	smsSender       notification.SMSSender
+	timeout         time.Duration
+	keys            *utils.KeySet
+	oidcProviders   map[string]SocialProvider
 }
 
 // NewService creates a new instance of the authentication service.
-func NewService(ur user.Repository, ar Repository) Service {
+func NewService(ur user.Repository, ar Repository, rs revocation.Store, m notification.Mailer, sms notification.SMSSender, keys *utils.KeySet, providers map[string]SocialProvider) Service {
 	return &service{
-		userRepo: ur,
-		authRepo: ar,
-		timeout:  time.Duration(config.AppConfig.DBTimeout) * time.Second,
-		secret:   config.AppConfig.JWTSecret,
+		userRepo:        ur,
+		authRepo:        ar,
+		revocationStore: rs,
+		mailer:          m,
+		smsSender:       sms,
+		timeout:         time.Duration(config.AppConfig.DBTimeout) * time.Second,
+		keys:            keys,
+		oidcProviders:   providers,
+	}
+}
+
+// newRefreshToken creates an opaque refresh token for the user's device in the given token family
+func (s *service) newRefreshToken(userID int64, familyID string, client ClientInfo) (*RefreshToken, error) {
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
 	}
+
+	return &RefreshToken{
+		UserID:    userID,
+		Token:     token,
+		FamilyID:  familyID,
+		UserAgent: client.UserAgent,
+		IPAddress: client.IPAddress,
+		ExpiresAt: time.Now().Add(refreshTokenExpiry),
+	}, nil
 }
 
 func (s *service) RegisterUser(c context.Context, req *RegisterUserReq) (*user.User, error) {
@@ -61,6 +179,10 @@ func (s *service) RegisterUser(c context.Context, req *RegisterUserReq) (*user.U
 		return nil, err
 	}
 
+	// A failed delivery doesn't fail the registration, the codes can be resent
+	_ = s.sendVerificationCode(ctx, createdUser, ChannelEmail)
+	_ = s.sendVerificationCode(ctx, createdUser, ChannelPhone)
+
 	res := &user.User{
 		ID:        createdUser.ID,
 		Name:      createdUser.Name,
@@ -78,34 +200,97 @@ func (s *service) Authenticate(c context.Context, req *LoginReq) (*LoginRes, err
 	ctx, cancel := context.WithTimeout(c, s.timeout)
 	defer cancel()
 
+	err := s.checkIPFailures(ctx, req.IPAddress)
+	if err != nil {
+		return nil, err
+	}
+
 	user, err := s.userRepo.GetByEmail(ctx, req.Email)
-	if err != nil || !utils.CheckPasswordHash(req.Password, user.Password) {
-		return nil, errors.New("invalid credentials")
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, s.recordLoginFailure(ctx, req.Email, req.ClientInfo, nil, LoginFailureUnknownEmail, ErrInvalidCredentials)
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if user.IsLocked(now) {
+		return nil, s.recordLoginFailure(ctx, req.Email, req.ClientInfo, &user.ID, LoginFailureLocked, &LockedError{RetryAfter: user.LockedUntil.Sub(now)})
+	}
+
+	if !utils.CheckPasswordHash(req.Password, user.Password) {
+		if err := s.lockAfterFailure(ctx, user.ID, now); err != nil {
+			return nil, err
+		}
+
+		return nil, s.recordLoginFailure(ctx, req.Email, req.ClientInfo, &user.ID, LoginFailureInvalidPassword, ErrInvalidCredentials)
+	}
+
+	return s.completeLogin(ctx, user, req.ClientInfo)
+}
+
+// completeLogin issues the tokens of a user who proved their first factor, or an MFA challenge when enabled
+func (s *service) completeLogin(ctx context.Context, u *user.User, client ClientInfo) (*LoginRes, error) {
+	if !config.AppConfig.AllowUnverifiedLogin && !u.IsVerified() {
+		return nil, ErrUnverifiedUser
+	}
+
+	// Accounts with two factor authentication get a challenge instead of the tokens, failures are reset only
+	// once the second factor is verified so the code can't be guessed by logging in again
+	mfa, err := s.authRepo.GetMFA(ctx, int(u.ID))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if mfa != nil && mfa.IsEnabled() {
+		mfaToken, err := utils.GenerateMFAToken(u.ID, s.keys, mfaTokenExpiry)
+		if err != nil {
+			return nil, err
+		}
+
+		res := &LoginRes{
+			MFARequired: true,
+			MFAToken:    mfaToken,
+		}
+
+		return res, nil
 	}
 
-	accessToken, err := utils.GenerateToken(user.ID, s.secret, time.Minute*15)
+	if u.FailedLoginAttempts > 0 {
+		err = s.userRepo.ResetFailedLogins(ctx, int(u.ID))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.issueTokens(ctx, u, client)
+}
+
+// issueTokens issues the access token and the refresh token of a new token family for the logged in user
+func (s *service) issueTokens(ctx context.Context, u *user.User, client ClientInfo) (*LoginRes, error) {
+	accessToken, err := utils.GenerateToken(u.ID, u.Role, s.keys, accessTokenExpiry)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := utils.GenerateToken(user.ID, s.secret, time.Hour*24*7)
+	// Every login starts a new refresh token family
+	familyID, err := utils.GenerateRandomToken(16)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = s.authRepo.Save(ctx, &RefreshToken{
-		UserID:    user.ID,
-		Token:     refreshToken,
-		ExpiresAt: time.Now().Add(time.Hour * 24 * 7),
-	})
+	refreshToken, err := s.newRefreshToken(u.ID, familyID, client)
+	if err != nil {
+		return nil, err
+	}
 
+	_, err = s.authRepo.Save(ctx, refreshToken)
 	if err != nil {
 		return nil, err
 	}
 
 	res := &LoginRes{
 		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
+		RefreshToken: refreshToken.Token,
 	}
 
 	return res, nil
@@ -116,23 +301,1142 @@ func (s *service) RefreshToken(c context.Context, req *RefreshTokenReq) (*Refres
 	defer cancel()
 
 	refreshToken, err := s.authRepo.FindByToken(ctx, req.RefreshToken)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// A rotated token presented again means it was stolen, so end the whole family
+	if refreshToken.RevokedAt != nil {
+		return nil, s.revokeFamily(ctx, refreshToken)
+	}
+
+	// Tokens issued before a password change or account deletion are revoked for the user
+	revoked, err := s.revocationStore.IsRevoked(ctx, "", refreshToken.UserID, refreshToken.CreatedAt)
 	if err != nil {
 		return nil, err
 	}
+	if revoked {
+		return nil, ErrInvalidRefreshToken
+	}
 
 	user, err := s.userRepo.GetByID(ctx, int(refreshToken.UserID))
 	if err != nil {
 		return nil, err
 	}
 
-	newAccessToken, err := utils.GenerateToken(user.ID, s.secret, time.Minute*15)
+	newAccessToken, err := utils.GenerateToken(user.ID, user.Role, s.keys, accessTokenExpiry)
+	if err != nil {
+		return nil, err
+	}
+
+	newRefreshToken, err := s.newRefreshToken(user.ID, refreshToken.FamilyID, req.ClientInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.authRepo.Rotate(ctx, refreshToken, newRefreshToken)
 	if err != nil {
+		if errors.Is(err, ErrRefreshTokenReused) {
+			return nil, s.revokeFamily(ctx, refreshToken)
+		}
 		return nil, err
 	}
 
 	res := &RefreshTokenRes{
-		AccessToken: newAccessToken,
+		AccessToken:  newAccessToken,
+		RefreshToken: newRefreshToken.Token,
+	}
+
+	return res, nil
+}
+
+// revokeFamily revokes every token of the reused refresh token's family and returns ErrRefreshTokenReused
+func (s *service) revokeFamily(ctx context.Context, refreshToken *RefreshToken) error {
+	if err := s.authRepo.RevokeFamily(ctx, int(refreshToken.UserID), refreshToken.FamilyID); err != nil {
+		return err
+	}
+
+	return ErrRefreshTokenReused
+}
+
+func (s *service) Logout(c context.Context, req *LogoutReq) (*utils.MessageRes, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	refreshToken, err := s.authRepo.FindByToken(ctx, req.RefreshToken)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// Revoking the family ends the session even if a rotation raced with the logout
+	err = s.authRepo.RevokeFamily(ctx, int(refreshToken.UserID), refreshToken.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Revoke the access token of the same user as well, invalid ones have already expired or can be ignored
+	if req.AccessToken != "" {
+		claims, err := utils.ValidateToken(req.AccessToken, s.keys)
+		if err == nil {
+			tokenClaims, err := utils.ParseTokenClaims(claims)
+			if err == nil && tokenClaims.UserID == refreshToken.UserID {
+				if err := s.revocationStore.Revoke(ctx, tokenClaims.ID, tokenClaims.ExpiresAt); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	res := &utils.MessageRes{
+		Success: true,
+		Message: "Logged out.",
+	}
+
+	return res, nil
+}
+
+func (s *service) LogoutAll(c context.Context, userID int) (*utils.MessageRes, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	err := s.authRepo.RevokeAllByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Access tokens issued so far are revoked along with the sessions
+	err = s.revocationStore.RevokeUser(ctx, int64(userID), time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	res := &utils.MessageRes{
+		Success: true,
+		Message: "Logged out from all devices.",
+	}
+
+	return res, nil
+}
+
+func (s *service) ListSessions(c context.Context, userID int) (*ListSessionRes, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	refreshTokens, err := s.authRepo.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]Session, 0, len(*refreshTokens))
+	for _, refreshToken := range *refreshTokens {
+		// Skip the sessions revoked for the user by a password change
+		revoked, err := s.revocationStore.IsRevoked(ctx, "", refreshToken.UserID, refreshToken.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if revoked {
+			continue
+		}
+
+		sessions = append(sessions, Session{
+			ID:        refreshToken.ID,
+			UserAgent: refreshToken.UserAgent,
+			IPAddress: refreshToken.IPAddress,
+			IssuedAt:  refreshToken.CreatedAt,
+			ExpiresAt: refreshToken.ExpiresAt,
+		})
+	}
+
+	res := &ListSessionRes{
+		Count:    len(sessions),
+		Sessions: &sessions,
+	}
+
+	return res, nil
+}
+
+func (s *service) GetJWKS() *utils.JWKS {
+	jwks := s.keys.JWKS()
+	return &jwks
+}
+
+func (s *service) ForgotPassword(c context.Context, req *ForgotPasswordReq) (*utils.MessageRes, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	// The same response is sent for unknown emails so registered accounts can't be discovered
+	res := &utils.MessageRes{
+		Success: true,
+		Message: "If the email is registered, a password reset link has been sent.",
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return res, nil
+		}
+		return nil, err
+	}
+
+	// Throttled requests get the same response as well, so the mailbox can't be flooded nor the throttle probed
+	latest, err := s.authRepo.GetLatestPasswordResetToken(ctx, int(user.ID))
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if latest != nil && time.Since(latest.CreatedAt) < resetResendInterval {
+		return res, nil
+	}
+
+	count, err := s.authRepo.CountPasswordResetTokensSince(ctx, int(user.ID), time.Now().Add(-24*time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxResetTokensPerDay {
+		return res, nil
+	}
+
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	resetToken := &PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(resetTokenExpiry),
+	}
+
+	_, err = s.authRepo.SavePasswordResetToken(ctx, resetToken)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.mailer.Send(ctx, &notification.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the link below to reset your password, it expires in %d minutes.\n\n%s/reset-password?token=%s\n\nIf you didn't request a password reset, you can ignore this email.",
+			user.Name, int(resetTokenExpiry.Minutes()), config.AppConfig.AppURL, token,
+		),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *service) ResetPassword(c context.Context, req *ResetPasswordReq) (*utils.MessageRes, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	hashedPassword, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := s.authRepo.ResetPassword(ctx, utils.HashToken(req.Token), hashedPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	// Access tokens issued so far are revoked along with the sessions
+	err = s.revocationStore.RevokeUser(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	res := &utils.MessageRes{
+		Success: true,
+		Message: "Password reset successfully.",
 	}
 
 	return res, nil
 }
+
+func (s *service) RequestEmailChange(c context.Context, req *ChangeEmailReq) (*utils.MessageRes, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	u, err := s.userRepo.GetByID(ctx, int(req.UserID))
+	if err != nil {
+		return nil, err
+	}
+
+	// A stolen access token alone must not be enough to take over the account
+	if !utils.CheckPasswordHash(req.CurrentPassword, u.Password) {
+		return nil, ErrInvalidCredentials
+	}
+
+	if strings.EqualFold(req.NewEmail, u.Email) {
+		return nil, ErrSameEmail
+	}
+
+	_, err = s.userRepo.GetByEmail(ctx, req.NewEmail)
+	if err == nil {
+		return nil, user.ErrEmailTaken
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	token, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	changeToken := &EmailChangeToken{
+		UserID:    u.ID,
+		NewEmail:  req.NewEmail,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(emailChangeTokenExpiry),
+	}
+
+	_, err = s.authRepo.SaveEmailChangeToken(ctx, changeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.mailer.Send(ctx, &notification.Message{
+		To:      req.NewEmail,
+		Subject: "Confirm your new email",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the link below to confirm this email for your account, it expires in %d hours.\n\n%s/confirm-email?token=%s\n\nIf you didn't request this change, you can ignore this email.",
+			u.Name, int(emailChangeTokenExpiry.Hours()), config.AppConfig.AppURL, token,
+		),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The current address is told so the owner can react if the request wasn't theirs
+	err = s.mailer.Send(ctx, &notification.Message{
+		To:      u.Email,
+		Subject: "Email change requested",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nA change of your account email to %s was requested, it takes effect once confirmed from the new address.\n\nIf you didn't request this change, reset your password right away.",
+			u.Name, req.NewEmail,
+		),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &utils.MessageRes{
+		Success: true,
+		Message: "A confirmation link has been sent to the new email.",
+	}
+
+	return res, nil
+}
+
+func (s *service) ConfirmEmailChange(c context.Context, req *ConfirmEmailChangeReq) (*utils.MessageRes, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	changeToken, err := s.authRepo.ConsumeEmailChangeToken(ctx, utils.HashToken(req.Token))
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := s.userRepo.GetByID(ctx, int(changeToken.UserID))
+	if err != nil {
+		return nil, err
+	}
+
+	// The email may have been registered by someone else since the change was requested
+	err = s.userRepo.UpdateEmail(ctx, int(u.ID), changeToken.NewEmail)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sessions started with the old email end along with it
+	err = s.authRepo.RevokeAllByUserID(ctx, int(u.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.revocationStore.RevokeUser(ctx, u.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	// The change is already done, so a failed notice doesn't fail the request
+	_ = s.mailer.Send(ctx, &notification.Message{
+		To:      u.Email,
+		Subject: "Your email has been changed",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nThe email of your account has been changed to %s and all sessions have been logged out.\n\nIf you didn't make this change, contact support right away.",
+			u.Name, changeToken.NewEmail,
+		),
+	})
+
+	res := &utils.MessageRes{
+		Success: true,
+		Message: "Email changed, log in again with the new email.",
+	}
+
+	return res, nil
+}
+
+// sendVerificationCode sends a new verification code to the user's email or phone, limiting how often codes are sent
+func (s *service) sendVerificationCode(ctx context.Context, u *user.User, channel string) error {
+	latest, err := s.authRepo.GetLatestVerificationCode(ctx, int(u.ID), channel)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if latest != nil && time.Since(latest.CreatedAt) < verificationResendInterval {
+		return ErrVerificationRateLimited
+	}
+
+	count, err := s.authRepo.CountVerificationCodesSince(ctx, int(u.ID), channel, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		return err
+	}
+	if count >= maxVerificationCodesPerDay {
+		return ErrVerificationRateLimited
+	}
+
+	code, err := utils.GenerateNumericCode(6)
+	if err != nil {
+		return err
+	}
+
+	target := u.Email
+	if channel == ChannelPhone {
+		target = u.Phone
+	}
+	if target == "" {
+		return ErrNoPhone
+	}
+
+	verificationCode := &VerificationCode{
+		UserID:    u.ID,
+		Channel:   channel,
+		Target:    target,
+		CodeHash:  utils.HashToken(code),
+		ExpiresAt: time.Now().Add(verificationCodeExpiry),
+	}
+
+	_, err = s.authRepo.SaveVerificationCode(ctx, verificationCode)
+	if err != nil {
+		return err
+	}
+
+	if channel == ChannelPhone {
+		return s.smsSender.Send(ctx, &notification.SMS{
+			To:   target,
+			Body: fmt.Sprintf("Your verification code is %s, it expires in %d minutes.", code, int(verificationCodeExpiry.Minutes())),
+		})
+	}
+
+	return s.mailer.Send(ctx, &notification.Message{
+		To:      target,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nYour verification code is %s, it expires in %d minutes.",
+			u.Name, code, int(verificationCodeExpiry.Minutes()),
+		),
+	})
+}
+
+// isChannelVerified reports whether the user's email or phone has been verified
+func isChannelVerified(u *user.User, channel string) bool {
+	if channel == ChannelPhone {
+		return u.PhoneVerifiedAt != nil
+	}
+
+	return u.EmailVerifiedAt != nil
+}
+
+func (s *service) ConfirmVerification(c context.Context, req *ConfirmVerificationReq) (*utils.MessageRes, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	u, err := s.userRepo.GetByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidVerificationCode
+		}
+		return nil, err
+	}
+
+	// Verified accounts have no pending code and fail like unknown emails
+	if isChannelVerified(u, req.Channel) {
+		return nil, ErrInvalidVerificationCode
+	}
+
+	code, err := s.authRepo.GetLatestVerificationCode(ctx, int(u.ID), req.Channel)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidVerificationCode
+		}
+		return nil, err
+	}
+
+	// Only the latest code is valid, and only for the email or phone it was sent to
+	target := u.Email
+	if req.Channel == ChannelPhone {
+		target = u.Phone
+	}
+	if code.ConsumedAt != nil || time.Now().After(code.ExpiresAt) || code.Target != target {
+		return nil, ErrInvalidVerificationCode
+	}
+
+	allowed, err := s.authRepo.RecordVerificationAttempt(ctx, code.ID, maxVerificationAttempts)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrVerificationAttemptsExceeded
+	}
+
+	if utils.HashToken(req.Code) != code.CodeHash {
+		return nil, ErrInvalidVerificationCode
+	}
+
+	err = s.authRepo.ConsumeVerificationCode(ctx, code.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Channel == ChannelPhone {
+		err = s.userRepo.MarkPhoneVerified(ctx, int(u.ID), target)
+	} else {
+		err = s.userRepo.MarkEmailVerified(ctx, int(u.ID), target)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := &utils.MessageRes{
+		Success: true,
+		Message: "Verified successfully.",
+	}
+
+	return res, nil
+}
+
+func (s *service) ResendVerification(c context.Context, req *ResendVerificationReq) (*utils.MessageRes, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	// The same response is sent for unknown emails and verified accounts so registered accounts can't be discovered
+	res := &utils.MessageRes{
+		Success: true,
+		Message: "If the account exists, a new verification code has been sent.",
+	}
+
+	u, err := s.userRepo.GetByEmail(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return res, nil
+		}
+		return nil, err
+	}
+
+	if isChannelVerified(u, req.Channel) {
+		return res, nil
+	}
+
+	err = s.sendVerificationCode(ctx, u, req.Channel)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// loginBackoff returns how long the account is locked after the consecutive failed logins, doubling with every
+// failure, short delays until the lockout threshold and lockouts of the configured duration from then on
+func loginBackoff(failures int) time.Duration {
+	threshold := config.AppConfig.LoginLockoutThreshold
+
+	var backoff time.Duration
+	if failures < threshold {
+		backoff = time.Second << min(failures-1, 16)
+	} else {
+		backoff = time.Duration(config.AppConfig.LoginLockoutMinutes) * time.Minute << min(failures-threshold, 16)
+	}
+
+	return min(backoff, maxLockoutDuration)
+}
+
+// checkIPFailures blocks clients failing across many accounts regardless of the account
+func (s *service) checkIPFailures(ctx context.Context, ipAddress string) error {
+	ipFailures, err := s.authRepo.CountFailedLoginsByIP(ctx, ipAddress, time.Now().Add(-loginIPWindow))
+	if err != nil {
+		return err
+	}
+	if ipFailures >= config.AppConfig.LoginIPMaxFailures {
+		return &LockedError{RetryAfter: loginIPWindow}
+	}
+
+	return nil
+}
+
+// lockAfterFailure counts a failed login of the user and locks the account for the backoff
+func (s *service) lockAfterFailure(ctx context.Context, userID int64, now time.Time) error {
+	failures, err := s.userRepo.IncrementFailedLogins(ctx, int(userID))
+	if err != nil {
+		return err
+	}
+
+	return s.userRepo.LockUntil(ctx, int(userID), now.Add(loginBackoff(failures)))
+}
+
+// recordLoginFailure stores the audit record of the failed login and returns the given login error
+func (s *service) recordLoginFailure(ctx context.Context, email string, client ClientInfo, userID *int64, reason string, loginErr error) error {
+	err := s.authRepo.RecordLoginAttempt(ctx, &LoginAttempt{
+		UserID:    userID,
+		Email:     email,
+		IPAddress: client.IPAddress,
+		UserAgent: client.UserAgent,
+		Reason:    reason,
+	})
+	if err != nil {
+		return err
+	}
+
+	return loginErr
+}
+
+func (s *service) UnlockUser(c context.Context, userID int) (*utils.MessageRes, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	// Check user exist before unlocking
+	_, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.userRepo.ResetFailedLogins(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &utils.MessageRes{
+		Success: true,
+		Message: "User unlocked.",
+	}
+
+	return res, nil
+}
+
+func (s *service) LockUser(c context.Context, req *LockUserReq) (*utils.MessageRes, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	// Check user exist before locking
+	_, err := s.userRepo.GetByID(ctx, int(req.UserID))
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.userRepo.LockUntil(ctx, int(req.UserID), time.Now().Add(time.Duration(req.Minutes)*time.Minute))
+	if err != nil {
+		return nil, err
+	}
+
+	// A lock only blocks new logins, so the existing sessions are ended as well
+	_, err = s.LogoutAll(ctx, int(req.UserID))
+	if err != nil {
+		return nil, err
+	}
+
+	res := &utils.MessageRes{
+		Success: true,
+		Message: "User locked.",
+	}
+
+	return res, nil
+}
+
+func (s *service) ForceLogout(c context.Context, userID int) (*utils.MessageRes, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	// Check user exist before ending the sessions
+	_, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.LogoutAll(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &utils.MessageRes{
+		Success: true,
+		Message: "User logged out from all devices.",
+	}
+
+	return res, nil
+}
+
+// verifySecondFactor checks an authenticator code or an unused recovery code of the user, each code is accepted once
+func (s *service) verifySecondFactor(ctx context.Context, mfa *UserMFA, code string) (bool, error) {
+	if len(code) == 6 {
+		step, ok := utils.ValidateTOTP(mfa.Secret, code, time.Now())
+		if !ok {
+			return false, nil
+		}
+
+		return s.authRepo.UseTOTPStep(ctx, int(mfa.UserID), step)
+	}
+
+	return s.authRepo.UseRecoveryCode(ctx, int(mfa.UserID), utils.HashToken(normalizeRecoveryCode(code)))
+}
+
+// normalizeRecoveryCode strips the formatting of a recovery code as typed by the user
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+func (s *service) VerifyMFA(c context.Context, req *VerifyMFAReq) (*LoginRes, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	err := s.checkIPFailures(ctx, req.IPAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := utils.ValidateToken(req.MFAToken, s.keys)
+	if err != nil {
+		return nil, ErrInvalidMFAToken
+	}
+
+	tokenClaims, err := utils.ParseTokenClaims(claims)
+	if err != nil || tokenClaims.Type != utils.TokenTypeMFA {
+		return nil, ErrInvalidMFAToken
+	}
+
+	revoked, err := s.revocationStore.IsRevoked(ctx, tokenClaims.ID, tokenClaims.UserID, tokenClaims.IssuedAt)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrInvalidMFAToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, int(tokenClaims.UserID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidMFAToken
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if user.IsLocked(now) {
+		return nil, s.recordLoginFailure(ctx, user.Email, req.ClientInfo, &user.ID, LoginFailureLocked, &LockedError{RetryAfter: user.LockedUntil.Sub(now)})
+	}
+
+	mfa, err := s.authRepo.GetMFA(ctx, int(user.ID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidMFAToken
+		}
+		return nil, err
+	}
+	if !mfa.IsEnabled() {
+		return nil, ErrInvalidMFAToken
+	}
+
+	verified, err := s.verifySecondFactor(ctx, mfa, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !verified {
+		if err := s.lockAfterFailure(ctx, user.ID, now); err != nil {
+			return nil, err
+		}
+
+		return nil, s.recordLoginFailure(ctx, user.Email, req.ClientInfo, &user.ID, LoginFailureInvalidMFACode, ErrInvalidMFACode)
+	}
+
+	// The challenge is single use
+	err = s.revocationStore.Revoke(ctx, tokenClaims.ID, tokenClaims.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.FailedLoginAttempts > 0 {
+		err = s.userRepo.ResetFailedLogins(ctx, int(user.ID))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.issueTokens(ctx, user, req.ClientInfo)
+}
+
+// checkMFALock returns the user unless the account is locked, codes of signed in users count towards the same
+// lockout as logins so the settings can't be used to guess them
+func (s *service) checkMFALock(ctx context.Context, userID int64) (*user.User, time.Time, error) {
+	now := time.Now()
+
+	u, err := s.userRepo.GetByID(ctx, int(userID))
+	if err != nil {
+		return nil, now, err
+	}
+	if u.IsLocked(now) {
+		return nil, now, &LockedError{RetryAfter: u.LockedUntil.Sub(now)}
+	}
+
+	return u, now, nil
+}
+
+// mfaFailure counts a wrong code of a signed in user and returns the invalid code error
+func (s *service) mfaFailure(ctx context.Context, userID int64, now time.Time) error {
+	if err := s.lockAfterFailure(ctx, userID, now); err != nil {
+		return err
+	}
+
+	return ErrInvalidMFACode
+}
+
+// resetMFAFailures clears the failures counted before a correct code
+func (s *service) resetMFAFailures(ctx context.Context, u *user.User) error {
+	if u.FailedLoginAttempts == 0 {
+		return nil
+	}
+
+	return s.userRepo.ResetFailedLogins(ctx, int(u.ID))
+}
+
+func (s *service) EnrollMFA(c context.Context, userID int) (*MFAEnrollRes, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.authRepo.SaveMFASecret(ctx, userID, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &MFAEnrollRes{
+		Secret:     secret,
+		OTPAuthURI: utils.TOTPURI(config.AppConfig.MFAIssuer, user.Email, secret),
+	}
+
+	return res, nil
+}
+
+func (s *service) ConfirmMFA(c context.Context, req *MFACodeReq) (*MFARecoveryCodesRes, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	mfa, err := s.authRepo.GetMFA(ctx, int(req.UserID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrMFANotEnabled
+		}
+		return nil, err
+	}
+	if mfa.IsEnabled() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	u, now, err := s.checkMFALock(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	step, ok := utils.ValidateTOTP(mfa.Secret, req.Code, now)
+	if !ok {
+		return nil, s.mfaFailure(ctx, u.ID, now)
+	}
+
+	err = s.resetMFAFailures(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes := make([]string, 0, recoveryCodesCount)
+	recoveryCodeHashes := make([]string, 0, recoveryCodesCount)
+	for i := 0; i < recoveryCodesCount; i++ {
+		code, err := utils.GenerateRandomToken(5)
+		if err != nil {
+			return nil, err
+		}
+
+		recoveryCodes = append(recoveryCodes, code[:5]+"-"+code[5:])
+		recoveryCodeHashes = append(recoveryCodeHashes, utils.HashToken(code))
+	}
+
+	err = s.authRepo.EnableMFA(ctx, int(req.UserID), step, recoveryCodeHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &MFARecoveryCodesRes{
+		RecoveryCodes: recoveryCodes,
+	}
+
+	return res, nil
+}
+
+func (s *service) DisableMFA(c context.Context, req *MFACodeReq) (*utils.MessageRes, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	mfa, err := s.authRepo.GetMFA(ctx, int(req.UserID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrMFANotEnabled
+		}
+		return nil, err
+	}
+	if !mfa.IsEnabled() {
+		return nil, ErrMFANotEnabled
+	}
+
+	u, now, err := s.checkMFALock(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	verified, err := s.verifySecondFactor(ctx, mfa, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !verified {
+		return nil, s.mfaFailure(ctx, u.ID, now)
+	}
+
+	err = s.resetMFAFailures(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.authRepo.DisableMFA(ctx, int(req.UserID))
+	if err != nil {
+		return nil, err
+	}
+
+	res := &utils.MessageRes{
+		Success: true,
+		Message: "Two factor authentication disabled.",
+	}
+
+	return res, nil
+}
+
+func (s *service) StartOIDCLogin(c context.Context, providerName string) (string, string, error) {
+	provider, ok := s.oidcProviders[providerName]
+	if !ok {
+		return "", "", ErrUnknownProvider
+	}
+
+	state, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return "", "", err
+	}
+	codeVerifier, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := utils.GenerateRandomToken(16)
+	if err != nil {
+		return "", "", err
+	}
+
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	err = s.authRepo.SaveOAuthState(ctx, &OAuthState{
+		State:        state,
+		Provider:     providerName,
+		CodeVerifier: codeVerifier,
+		Nonce:        nonce,
+		ExpiresAt:    time.Now().Add(oauthStateExpiry),
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	// The provider calls are bounded by the http client instead of the database timeout
+	authURL, err := provider.AuthCodeURL(c, state, nonce, CodeChallenge(codeVerifier))
+	if err != nil {
+		return "", "", err
+	}
+
+	return authURL, state, nil
+}
+
+func (s *service) CompleteOIDCLogin(c context.Context, req *OIDCCallbackReq) (string, error) {
+	provider, ok := s.oidcProviders[req.Provider]
+	if !ok {
+		return "", ErrUnknownProvider
+	}
+
+	// The state must come back to the browser that started the login, so a leaked callback URL can't be redeemed
+	if req.CookieState == "" || subtle.ConstantTimeCompare([]byte(req.CookieState), []byte(req.State)) != 1 {
+		return "", ErrInvalidOAuthState
+	}
+
+	stateCtx, cancelState := context.WithTimeout(c, s.timeout)
+	state, err := s.authRepo.ConsumeOAuthState(stateCtx, req.State)
+	cancelState()
+	if err != nil {
+		return "", err
+	}
+	if state.Provider != req.Provider {
+		return "", ErrInvalidOAuthState
+	}
+
+	if req.Error != "" {
+		return "", fmt.Errorf("%w: %s", ErrSocialLoginFailed, req.Error)
+	}
+
+	claims, err := provider.Exchange(c, req.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrSocialLoginFailed, err)
+	}
+
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	u, err := s.getOrCreateOIDCUser(ctx, req.Provider, claims)
+	if err != nil {
+		return "", err
+	}
+
+	code, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.authRepo.SaveOAuthLoginCode(ctx, &OAuthLoginCode{
+		CodeHash:  utils.HashToken(code),
+		UserID:    u.ID,
+		ExpiresAt: time.Now().Add(oauthLoginCodeExpiry),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+func (s *service) ExchangeOIDCLoginCode(c context.Context, req *OIDCExchangeReq) (*LoginRes, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	loginCode, err := s.authRepo.ConsumeOAuthLoginCode(ctx, utils.HashToken(req.Code))
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := s.userRepo.GetByID(ctx, int(loginCode.UserID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidLoginCode
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if u.IsLocked(now) {
+		return nil, &LockedError{RetryAfter: u.LockedUntil.Sub(now)}
+	}
+
+	return s.completeLogin(ctx, u, req.ClientInfo)
+}
+
+// getOrCreateOIDCUser returns the user linked to the provider identity. On the first login the identity is linked
+// to the account of the same verified email, or a new account is created for it
+func (s *service) getOrCreateOIDCUser(ctx context.Context, provider string, claims *OIDCClaims) (*user.User, error) {
+	identity, err := s.authRepo.GetIdentity(ctx, provider, claims.Subject)
+	if err == nil {
+		u, err := s.userRepo.GetByID(ctx, int(identity.UserID))
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, ErrSocialLoginFailed
+			}
+			return nil, err
+		}
+
+		return u, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	// Only an email verified by the provider can claim an account
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, ErrEmailNotProvided
+	}
+
+	u, err := s.userRepo.GetByEmail(ctx, claims.Email)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		u, err = s.createOIDCUser(ctx, claims)
+		if err != nil {
+			return nil, err
+		}
+	} else if !u.IsVerified() {
+		// Linking an unverified account would hand it, and whoever registered it, the provider's identity
+		return nil, ErrAccountLinkRequired
+	}
+
+	err = s.userRepo.MarkEmailVerified(ctx, int(u.ID), claims.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.authRepo.SaveIdentity(ctx, &Identity{
+		UserID:   u.ID,
+		Provider: provider,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.userRepo.GetByID(ctx, int(u.ID))
+}
+
+// createOIDCUser creates the account of a new social login user with an unusable random password
+func (s *service) createOIDCUser(ctx context.Context, claims *OIDCClaims) (*user.User, error) {
+	password, err := utils.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	name := claims.Name
+	if name == "" {
+		name, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	u := &user.User{
+		Name:     name,
+		Email:    claims.Email,
+		Role:     user.RoleUser,
+		Password: hashedPassword,
+	}
+
+	return s.userRepo.Create(ctx, u)
+}
@@ -0,0 +1,45 @@
+package cart
+
+import "time"
+
+// CartItem represents a product added to the user's cart.
+type CartItem struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	ProductID int64     `json:"product_id"`
+	Quantity  int       `json:"quantity"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CartItemRes represents a cart item joined with the current product details.
+type CartItemRes struct {
+	ID          int64     `json:"id"`
+	ProductID   int64     `json:"product_id"`
+	ProductName string    `json:"product_name"`
+	Price       float64   `json:"price"`
+	Quantity    int       `json:"quantity"`
+	LineTotal   float64   `json:"line_total"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// AddCartItemReq represents the request payload for adding a product to the cart.
+type AddCartItemReq struct {
+	UserID    int64 `json:"user_id,omitempty"`
+	ProductID int64 `json:"product_id" validate:"required"`
+	Quantity  int   `json:"quantity" validate:"required,gt=0,lte=100"`
+}
+
+// UpdateCartItemReq represents the request payload for changing a cart item quantity.
+type UpdateCartItemReq struct {
+	ID       int64 `json:"id,omitempty"`
+	UserID   int64 `json:"user_id,omitempty"`
+	Quantity int   `json:"quantity" validate:"required,gt=0,lte=100"`
+}
+
+// CartRes struct for returning the cart items along with the subtotal
+type CartRes struct {
+	Count    int            `json:"count"`
+	Items    *[]CartItemRes `json:"items"`
+	SubTotal float64        `json:"sub_total"`
+}
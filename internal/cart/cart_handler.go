@@ -0,0 +1,217 @@
+package cart
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/aslam-ep/go-e-commerce/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+// Handler handles HTTP requests related to user cart.
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new instance of the Handler with the provided cart service.
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+func (h *Handler) getIDsFromParam(r *http.Request) (int, int, error) {
+	userIDStr := chi.URLParam(r, "user_id")
+	userID, err := strconv.Atoi(userIDStr)
+	if err != nil {
+		return -1, -1, err
+	}
+
+	itemIDStr := chi.URLParam(r, "item_id")
+	itemID, err := strconv.Atoi(itemIDStr)
+	if err != nil {
+		return -1, -1, err
+	}
+
+	return itemID, userID, nil
+}
+
+// GetCart godoc
+// @Summary      Get cart
+// @Description  Get the cart items of the authenticated user with line totals and subtotal
+// @Tags         Cart
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        user_id  path      int  true  "User ID"
+// @Success      200      {object}  CartRes
+// @Failure      400      {object}  utils.MessageRes
+// @Failure      401      {object}  utils.MessageRes
+// @Failure      500      {object}  utils.MessageRes
+// @Router       /users/{user_id}/cart/ [get]
+func (h *Handler) GetCart(w http.ResponseWriter, r *http.Request) {
+	userIDStr := chi.URLParam(r, "user_id")
+	userID, err := strconv.Atoi(userIDStr)
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.GetCart(r.Context(), userID)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// AddItem godoc
+// @Summary      Add item to cart
+// @Description  Add a product to the authenticated user's cart, increasing the quantity when already present
+// @Tags         Cart
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        user_id  path      int             true  "User ID"
+// @Param        body     body      AddCartItemReq  true  "Add cart item request"
+// @Success      200      {object}  CartItem
+// @Failure      400      {object}  utils.MessageRes
+// @Failure      401      {object}  utils.MessageRes
+// @Failure      404      {object}  utils.MessageRes
+// @Failure      409      {object}  utils.MessageRes
+// @Router       /users/{user_id}/cart/add [post]
+func (h *Handler) AddItem(w http.ResponseWriter, r *http.Request) {
+	var itemReq AddCartItemReq
+	if err := utils.ReadFromRequest(r, &itemReq); err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userIDStr := chi.URLParam(r, "user_id")
+	userID, err := strconv.Atoi(userIDStr)
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	itemReq.UserID = int64(userID)
+
+	if err := utils.Validate.Struct(itemReq); err != nil {
+		utils.WriteValidationError(w, r, err)
+		return
+	}
+
+	res, err := h.service.AddItem(r.Context(), &itemReq)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// UpdateItemQuantity godoc
+// @Summary      Change cart item quantity
+// @Description  Change the quantity of a cart item of the authenticated user
+// @Tags         Cart
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        user_id  path      int                true  "User ID"
+// @Param        item_id  path      int                true  "Cart item ID"
+// @Param        body     body      UpdateCartItemReq  true  "Update cart item request"
+// @Success      200      {object}  CartItem
+// @Failure      400      {object}  utils.MessageRes
+// @Failure      401      {object}  utils.MessageRes
+// @Failure      404      {object}  utils.MessageRes
+// @Failure      409      {object}  utils.MessageRes
+// @Router       /users/{user_id}/cart/{item_id}/update [put]
+func (h *Handler) UpdateItemQuantity(w http.ResponseWriter, r *http.Request) {
+	var itemReq UpdateCartItemReq
+	if err := utils.ReadFromRequest(r, &itemReq); err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	itemID, userID, err := h.getIDsFromParam(r)
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	itemReq.ID = int64(itemID)
+	itemReq.UserID = int64(userID)
+
+	if err := utils.Validate.Struct(itemReq); err != nil {
+		utils.WriteValidationError(w, r, err)
+		return
+	}
+
+	res, err := h.service.UpdateItemQuantity(r.Context(), &itemReq)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// RemoveItem godoc
+// @Summary      Remove cart item
+// @Description  Remove an item from the authenticated user's cart
+// @Tags         Cart
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        user_id  path      int  true  "User ID"
+// @Param        item_id  path      int  true  "Cart item ID"
+// @Success      200      {object}  utils.MessageRes
+// @Failure      400      {object}  utils.MessageRes
+// @Failure      401      {object}  utils.MessageRes
+// @Failure      404      {object}  utils.MessageRes
+// @Router       /users/{user_id}/cart/{item_id}/delete [delete]
+func (h *Handler) RemoveItem(w http.ResponseWriter, r *http.Request) {
+	itemID, userID, err := h.getIDsFromParam(r)
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.RemoveItem(r.Context(), itemID, userID)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// ClearCart godoc
+// @Summary      Clear cart
+// @Description  Remove all the items from the authenticated user's cart
+// @Tags         Cart
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        user_id  path      int  true  "User ID"
+// @Success      200      {object}  utils.MessageRes
+// @Failure      400      {object}  utils.MessageRes
+// @Failure      401      {object}  utils.MessageRes
+// @Failure      500      {object}  utils.MessageRes
+// @Router       /users/{user_id}/cart/clear [delete]
+func (h *Handler) ClearCart(w http.ResponseWriter, r *http.Request) {
+	userIDStr := chi.URLParam(r, "user_id")
+	userID, err := strconv.Atoi(userIDStr)
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.ClearCart(r.Context(), userID)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
@@ -0,0 +1,163 @@
+package cart
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/aslam-ep/go-e-commerce/apperrors"
+)
+
+// Repository interface for cart repository
+type Repository interface {
+	// Add Add the product to the user's cart, increasing the quantity when it is already present, returns
+	// ErrInsufficientStock when the resulting quantity is more than the product stock
+	Add(ctx context.Context, item *CartItem) (*CartItem, error)
+
+	// GetAll Get all the cart items of the given user along with the product details
+	GetAll(ctx context.Context, userID int) (*[]CartItemRes, error)
+
+	// GetByID Get cart item by the given ID and user ID
+	GetByID(ctx context.Context, id int, userID int) (*CartItem, error)
+
+	// UpdateQuantity Update the quantity of the cart item
+	UpdateQuantity(ctx context.Context, item *CartItem) (*CartItem, error)
+
+	// Delete Remove the cart item
+	Delete(ctx context.Context, id int, userID int) error
+
+	// Clear Remove all the cart items of the given user
+	Clear(ctx context.Context, userID int) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+// NewRepository initialize and returns cart repository
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Add(ctx context.Context, item *CartItem) (*CartItem, error) {
+	// The stock check is part of the upsert so concurrent adds are serialized on the cart row and can't exceed the stock
+	upsertQuery := `INSERT INTO cart_items(user_id, product_id, quantity)
+		SELECT $1, id, $3 FROM products WHERE id = $2 AND is_deleted = false AND stock_count >= $3
+		ON CONFLICT (user_id, product_id) DO UPDATE SET quantity = cart_items.quantity + EXCLUDED.quantity, updated_at = CURRENT_TIMESTAMP
+		WHERE cart_items.quantity + EXCLUDED.quantity <= (SELECT stock_count FROM products WHERE id = EXCLUDED.product_id)
+		RETURNING id, quantity, created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, upsertQuery,
+		item.UserID,
+		item.ProductID,
+		item.Quantity,
+	).Scan(
+		&item.ID,
+		&item.Quantity,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInsufficientStock
+	}
+	if err != nil {
+		return nil, apperrors.FromDB(err, "cart item")
+	}
+
+	return item, nil
+}
+
+func (r *repository) GetAll(ctx context.Context, userID int) (*[]CartItemRes, error) {
+	selectByUserIDQuery := `SELECT ci.id, ci.product_id, p.name, p.price, ci.quantity, ci.updated_at
+		FROM cart_items ci
+		JOIN products p ON p.id = ci.product_id
+		WHERE ci.user_id = $1 AND p.is_deleted = false
+		ORDER BY ci.id;`
+
+	rows, err := r.db.QueryContext(ctx, selectByUserIDQuery, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []CartItemRes{}
+	for rows.Next() {
+		var item CartItemRes
+		if err := rows.Scan(
+			&item.ID,
+			&item.ProductID,
+			&item.ProductName,
+			&item.Price,
+			&item.Quantity,
+			&item.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &items, nil
+}
+
+func (r *repository) GetByID(ctx context.Context, id int, userID int) (*CartItem, error) {
+	var item CartItem
+	selectByIDQuery := `SELECT id, user_id, product_id, quantity, created_at, updated_at FROM cart_items WHERE id = $1 AND user_id = $2;`
+
+	err := r.db.QueryRowContext(ctx, selectByIDQuery, id, userID).Scan(
+		&item.ID,
+		&item.UserID,
+		&item.ProductID,
+		&item.Quantity,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, apperrors.FromDB(err, "cart item")
+	}
+
+	return &item, nil
+}
+
+func (r *repository) UpdateQuantity(ctx context.Context, item *CartItem) (*CartItem, error) {
+	item.UpdatedAt = time.Now()
+	updateQuery := `UPDATE cart_items SET quantity = $1, updated_at = $2 WHERE id = $3 AND user_id = $4;`
+
+	_, err := r.db.ExecContext(ctx, updateQuery,
+		item.Quantity,
+		item.UpdatedAt,
+		item.ID,
+		item.UserID,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (r *repository) Delete(ctx context.Context, id int, userID int) error {
+	deleteQuery := `DELETE FROM cart_items WHERE id = $1 AND user_id = $2;`
+
+	_, err := r.db.ExecContext(ctx, deleteQuery, id, userID)
+
+	return err
+}
+
+func (r *repository) Clear(ctx context.Context, userID int) error {
+	clearQuery := `DELETE FROM cart_items WHERE user_id = $1;`
+
+	_, err := r.db.ExecContext(ctx, clearQuery, userID)
+
+	return err
+}
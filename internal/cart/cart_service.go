@@ -0,0 +1,178 @@
+package cart
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/aslam-ep/go-e-commerce/apperrors"
+	"github.com/aslam-ep/go-e-commerce/config"
+	"github.com/aslam-ep/go-e-commerce/internal/product"
+	"github.com/aslam-ep/go-e-commerce/utils"
+)
+
+// ErrInsufficientStock is returned when the requested quantity is more than the product stock.
+var ErrInsufficientStock = apperrors.Conflict("insufficient_stock", "requested quantity is not available in stock")
+
+// Service interface defines the methods required for cart services.
+type Service interface {
+	// GetCart Get the cart items of the given user along with line totals and subtotal
+	GetCart(c context.Context, userID int) (*CartRes, error)
+
+	// AddItem Add the product to the user's cart and returns the cart item
+	AddItem(c context.Context, req *AddCartItemReq) (*CartItem, error)
+
+	// UpdateItemQuantity Change the quantity of the cart item and returns the updated item
+	UpdateItemQuantity(c context.Context, req *UpdateCartItemReq) (*CartItem, error)
+
+	// RemoveItem Remove the cart item based on given ID
+	RemoveItem(c context.Context, id int, userID int) (*utils.MessageRes, error)
+
+	// ClearCart Remove all the items from the user's cart
+	ClearCart(c context.Context, userID int) (*utils.MessageRes, error)
+}
+
+type service struct {
+	repository  Repository
+	productRepo product.Repository
+	timeout     time.Duration
+}
+
+// NewService creates a new instance of the cart service.
+func NewService(cartRepo Repository, productRepo product.Repository) Service {
+	return &service{
+		repository:  cartRepo,
+		productRepo: productRepo,
+		timeout:     time.Duration(config.AppConfig.DBTimeout) * time.Second,
+	}
+}
+
+// roundAmount rounds the amount to two decimal places
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// checkStock makes sure the product exists and has enough stock for the quantity
+func (s *service) checkStock(ctx context.Context, productID int, quantity int) error {
+	p, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+
+	if quantity > p.StockCount {
+		return ErrInsufficientStock
+	}
+
+	return nil
+}
+
+func (s *service) GetCart(c context.Context, userID int) (*CartRes, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	items, err := s.repository.GetAll(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var subTotal float64
+	for i := range *items {
+		item := &(*items)[i]
+		item.LineTotal = roundAmount(item.Price * float64(item.Quantity))
+		subTotal += item.LineTotal
+	}
+
+	res := &CartRes{
+		Count:    len(*items),
+		Items:    items,
+		SubTotal: roundAmount(subTotal),
+	}
+
+	return res, nil
+}
+
+func (s *service) AddItem(c context.Context, req *AddCartItemReq) (*CartItem, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	// The quantity already in the cart is checked by the repository together with the upsert
+	if err := s.checkStock(ctx, int(req.ProductID), req.Quantity); err != nil {
+		return nil, err
+	}
+
+	i := &CartItem{
+		UserID:    req.UserID,
+		ProductID: req.ProductID,
+		Quantity:  req.Quantity,
+	}
+
+	item, err := s.repository.Add(ctx, i)
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (s *service) UpdateItemQuantity(c context.Context, req *UpdateCartItemReq) (*CartItem, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	item, err := s.repository.GetByID(ctx, int(req.ID), int(req.UserID))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkStock(ctx, int(item.ProductID), req.Quantity); err != nil {
+		return nil, err
+	}
+
+	item.Quantity = req.Quantity
+
+	updatedItem, err := s.repository.UpdateQuantity(ctx, item)
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedItem, nil
+}
+
+func (s *service) RemoveItem(c context.Context, id int, userID int) (*utils.MessageRes, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	_, err := s.repository.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repository.Delete(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &utils.MessageRes{
+		Success: true,
+		Message: fmt.Sprintf("Cart item(%d) removed.", id),
+	}
+
+	return res, nil
+}
+
+func (s *service) ClearCart(c context.Context, userID int) (*utils.MessageRes, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	err := s.repository.Clear(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &utils.MessageRes{
+		Success: true,
+		Message: "Cart cleared.",
+	}
+
+	return res, nil
+}
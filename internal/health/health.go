@@ -0,0 +1,72 @@
+package health
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Statuses of the health checks and of the server
+const (
+	StatusUp       = "up"
+	StatusDown     = "down"
+	StatusAlive    = "alive"
+	StatusReady    = "ready"
+	StatusNotReady = "not_ready"
+)
+
+// PoolStats holds the connection pool statistics of the database.
+type PoolStats struct {
+	MaxOpenConnections int    `json:"max_open_connections"`
+	OpenConnections    int    `json:"open_connections"`
+	InUse              int    `json:"in_use"`
+	Idle               int    `json:"idle"`
+	WaitCount          int64  `json:"wait_count"`
+	WaitDuration       string `json:"wait_duration"`
+}
+
+// MigrationStatus holds the version of the applied schema migrations.
+type MigrationStatus struct {
+	Version int64 `json:"version"`
+	Dirty   bool  `json:"dirty"`
+}
+
+// Check holds the result of a single dependency check.
+type Check struct {
+	Status     string           `json:"status"`
+	Error      string           `json:"error,omitempty"`
+	DurationMs int64            `json:"duration_ms"`
+	Pool       *PoolStats       `json:"pool,omitempty"`
+	Migration  *MigrationStatus `json:"migration,omitempty"`
+}
+
+// LivenessRes struct for the response of the liveness probe
+type LivenessRes struct {
+	Status string    `json:"status"`
+	Time   time.Time `json:"time"`
+}
+
+// ReadinessRes struct for the response of the readiness probe along with every check
+type ReadinessRes struct {
+	Status string           `json:"status"`
+	Checks map[string]Check `json:"checks"`
+}
+
+// Readiness reports whether the server should receive traffic, it flips to not ready before the server drains.
+type Readiness struct {
+	ready atomic.Bool
+}
+
+// NewReadiness initialize and returns a Readiness that is not ready yet
+func NewReadiness() *Readiness {
+	return &Readiness{}
+}
+
+// SetReady marks the server as ready or not ready for traffic.
+func (r *Readiness) SetReady(ready bool) {
+	r.ready.Store(ready)
+}
+
+// IsReady reports whether the server is ready for traffic.
+func (r *Readiness) IsReady() bool {
+	return r.ready.Load()
+}
@@ -0,0 +1,51 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/aslam-ep/go-e-commerce/utils"
+)
+
+// Handler handles the HTTP health probes.
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new instance of the Handler with the provided health service.
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+// Liveness      godoc
+// @Summary      Liveness probe
+// @Description  Report the process is alive without checking any dependency
+// @Tags         Health
+// @Produce      json
+// @Success      200  {object}  LivenessRes
+// @Router       /healthz [get]
+func (h *Handler) Liveness(w http.ResponseWriter, r *http.Request) {
+	utils.WriteResponse(w, http.StatusOK, h.service.Liveness())
+}
+
+// Readiness     godoc
+// @Summary      Readiness probe
+// @Description  Check the database connection and schema migrations, reporting every check along with the connection pool
+// @Tags         Health
+// @Produce      json
+// @Success      200  {object}  ReadinessRes
+// @Failure      503  {object}  ReadinessRes
+// @Router       /readyz [get]
+func (h *Handler) Readiness(w http.ResponseWriter, r *http.Request) {
+	res := h.service.Readiness(r.Context())
+
+	status := http.StatusOK
+	if res.Status != StatusReady {
+		status = http.StatusServiceUnavailable
+	}
+
+	// Probes must always see the current state
+	w.Header().Set("Cache-Control", "no-store")
+	utils.WriteResponse(w, status, res)
+}
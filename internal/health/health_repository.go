@@ -0,0 +1,58 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Repository interface for health repository
+type Repository interface {
+	// Ping Verify the database connection is alive
+	Ping(ctx context.Context) error
+
+	// GetPoolStats Get the connection pool statistics of the database
+	GetPoolStats() *PoolStats
+
+	// GetMigrationStatus Get the latest applied schema migration version
+	GetMigrationStatus(ctx context.Context) (*MigrationStatus, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+// NewRepository initialize and returns health repository
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
+func (r *repository) GetPoolStats() *PoolStats {
+	stats := r.db.Stats()
+
+	return &PoolStats{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDuration:       stats.WaitDuration.String(),
+	}
+}
+
+func (r *repository) GetMigrationStatus(ctx context.Context) (*MigrationStatus, error) {
+	var status MigrationStatus
+	selectQuery := `SELECT version, dirty FROM schema_migrations LIMIT 1;`
+
+	err := r.db.QueryRowContext(ctx, selectQuery).Scan(&status.Version, &status.Dirty)
+	if err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
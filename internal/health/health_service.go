@@ -0,0 +1,120 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/aslam-ep/go-e-commerce/config"
+)
+
+// Service interface defines the methods required for health services.
+type Service interface {
+	// Liveness Report the process is alive without checking any dependency
+	Liveness() *LivenessRes
+
+	// Readiness Check every dependency and report whether the server is ready for traffic
+	Readiness(c context.Context) *ReadinessRes
+}
+
+type service struct {
+	repository Repository
+	readiness  *Readiness
+	timeout    time.Duration
+}
+
+// NewService creates a new instance of the health service.
+func NewService(healthRepo Repository, readiness *Readiness) Service {
+	return &service{
+		repository: healthRepo,
+		readiness:  readiness,
+		timeout:    time.Duration(config.AppConfig.DBTimeout) * time.Second,
+	}
+}
+
+func (s *service) Liveness() *LivenessRes {
+	return &LivenessRes{
+		Status: StatusAlive,
+		Time:   time.Now(),
+	}
+}
+
+func (s *service) Readiness(c context.Context) *ReadinessRes {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	res := &ReadinessRes{
+		Status: StatusReady,
+		Checks: map[string]Check{
+			"server":     s.checkServer(),
+			"database":   s.checkDatabase(ctx),
+			"migrations": s.checkMigrations(ctx),
+		},
+	}
+
+	for _, check := range res.Checks {
+		if check.Status != StatusUp {
+			res.Status = StatusNotReady
+			break
+		}
+	}
+
+	return res
+}
+
+// checkServer reports the server down once it started draining
+func (s *service) checkServer() Check {
+	if !s.readiness.IsReady() {
+		return Check{Status: StatusDown, Error: "server is not accepting traffic"}
+	}
+
+	return Check{Status: StatusUp}
+}
+
+// checkDatabase pings the database and reports the connection pool statistics
+func (s *service) checkDatabase(ctx context.Context) Check {
+	start := time.Now()
+	err := s.repository.Ping(ctx)
+
+	check := Check{
+		Status:     StatusUp,
+		DurationMs: time.Since(start).Milliseconds(),
+		Pool:       s.repository.GetPoolStats(),
+	}
+	if err != nil {
+		log.Println("Readiness database check failed:", err)
+		check.Status = StatusDown
+		check.Error = "database is unavailable"
+	}
+
+	return check
+}
+
+// checkMigrations reports the applied schema version, a dirty version means a migration failed halfway
+func (s *service) checkMigrations(ctx context.Context) Check {
+	start := time.Now()
+	migration, err := s.repository.GetMigrationStatus(ctx)
+
+	check := Check{
+		Status:     StatusUp,
+		DurationMs: time.Since(start).Milliseconds(),
+		Migration:  migration,
+	}
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		check.Status = StatusDown
+		check.Error = "no migrations applied"
+	case err != nil:
+		log.Println("Readiness migrations check failed:", err)
+		check.Status = StatusDown
+		check.Error = "migration status is unavailable"
+	case migration.Dirty:
+		check.Status = StatusDown
+		check.Error = "migration is dirty"
+	}
+
+	return check
+}
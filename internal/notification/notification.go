@@ -0,0 +1,50 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Message represents an email to be delivered to a single recipient.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers emails to users.
+type Mailer interface {
+	// Send delivers the message to its recipient.
+	Send(ctx context.Context, msg *Message) error
+}
+
+// SMS represents a text message to be delivered to a single phone number.
+type SMS struct {
+	To   string
+	Body string
+}
+
+// SMSSender delivers text messages to users.
+type SMSSender interface {
+	// Send delivers the text message to its recipient.
+	Send(ctx context.Context, msg *SMS) error
+}
+
+// NewMailer initialize and returns the mailer of the given kind, file writes to the given path and log writes to the server log.
+// An unset kind falls back to the log mailer in development only, any other environment must choose one explicitly.
+func NewMailer(kind, path, env string) (Mailer, error) {
+	switch kind {
+	case "file":
+		return NewFileMailer(path), nil
+	case "log":
+		return NewLogMailer(), nil
+	case "":
+		if env == "development" {
+			return NewLogMailer(), nil
+		}
+		return nil, errors.New("no mailer configured, set MAILER to log or file")
+	default:
+		return nil, fmt.Errorf("unknown mailer %q, expected log or file", kind)
+	}
+}
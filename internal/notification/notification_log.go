@@ -0,0 +1,34 @@
+package notification
+
+import (
+	"context"
+	"log"
+)
+
+// logMailer writes the emails to the application log, meant for local development.
+type logMailer struct{}
+
+// NewLogMailer initialize and returns a mailer logging every message
+func NewLogMailer() Mailer {
+	return &logMailer{}
+}
+
+func (m *logMailer) Send(ctx context.Context, msg *Message) error {
+	log.Printf("Mail to: %s\nSubject: %s\n\n%s\n", msg.To, msg.Subject, msg.Body)
+
+	return nil
+}
+
+// logSMSSender writes the text messages to the application log, meant for local development.
+type logSMSSender struct{}
+
+// NewLogSMSSender initialize and returns a sms sender logging every message
+func NewLogSMSSender() SMSSender {
+	return &logSMSSender{}
+}
+
+func (s *logSMSSender) Send(ctx context.Context, msg *SMS) error {
+	log.Printf("SMS to: %s\n%s\n", msg.To, msg.Body)
+
+	return nil
+}
@@ -0,0 +1,263 @@
+package order
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aslam-ep/go-e-commerce/apperrors"
+	"github.com/aslam-ep/go-e-commerce/internal/address"
+	"github.com/aslam-ep/go-e-commerce/utils"
+)
+
+// Order statuses, an order moves between them following allowedTransitions.
+const (
+	StatusPending   = "pending"
+	StatusPaid      = "paid"
+	StatusPacked    = "packed"
+	StatusShipped   = "shipped"
+	StatusDelivered = "delivered"
+	StatusCancelled = "cancelled"
+	StatusRefunded  = "refunded"
+)
+
+// allowedTransitions holds the statuses an order can move to from each status.
+var allowedTransitions = map[string][]string{
+	StatusPending:   {StatusPaid, StatusCancelled},
+	StatusPaid:      {StatusPacked, StatusCancelled, StatusRefunded},
+	StatusPacked:    {StatusShipped, StatusCancelled},
+	StatusShipped:   {StatusDelivered},
+	StatusDelivered: {StatusRefunded},
+	StatusCancelled: {},
+	StatusRefunded:  {},
+}
+
+// Fulfilment statuses of an order item, updated by the vendor owning the product.
+const (
+	FulfilmentPending          = "pending"
+	FulfilmentPartiallyPacked  = "partially_packed"
+	FulfilmentPacked           = "packed"
+	FulfilmentPartiallyShipped = "partially_shipped"
+	FulfilmentShipped          = "shipped"
+)
+
+// CanTransition reports whether an order can move from one status to another.
+func CanTransition(from, to string) bool {
+	for _, status := range allowedTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+var (
+	// ErrOrderNotFound is returned when the order doesn't exist for the user.
+	ErrOrderNotFound = apperrors.NotFound("order_not_found", "order not found")
+
+	// ErrInvalidTransition is returned when the order can't move to the requested status.
+	ErrInvalidTransition = apperrors.Conflict("invalid_status_transition", "invalid order status transition")
+
+	// ErrOrderItemNotFound is returned when the order item doesn't exist for the vendor.
+	ErrOrderItemNotFound = apperrors.NotFound("order_item_not_found", "order item not found")
+
+	// ErrEmptyCart is returned when checking out a cart without any available items.
+	ErrEmptyCart = apperrors.Validation("empty_cart", "cart is empty")
+
+	// ErrInvalidAddress is returned when the address doesn't belong to the user.
+	ErrInvalidAddress = apperrors.Validation("invalid_address", "address doesn't belong to the user")
+
+	// ErrUnverifiedUser is returned when a user without a verified email checks out while it isn't allowed.
+	ErrUnverifiedUser = apperrors.Forbidden("unverified_user", "verify your email before placing an order")
+)
+
+// InsufficientStockError is returned when a product doesn't have enough stock for the order.
+type InsufficientStockError struct {
+	ProductID int64
+	Requested int
+	Available int
+}
+
+func (e *InsufficientStockError) Error() string {
+	return fmt.Sprintf("insufficient stock for product(%d): requested %d, available %d", e.ProductID, e.Requested, e.Available)
+}
+
+// Order represents an order placed by the user.
+type Order struct {
+	ID            int64        `json:"id"`
+	UserID        int64        `json:"user_id"`
+	AddressID     int64        `json:"address_id"`
+	TotalAmount   float64      `json:"total_amount"`
+	Status        string       `json:"status"`
+	PaymentMethod string       `json:"payment_method"`
+	Items         *[]OrderItem `json:"items,omitempty"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
+
+// OrderItem represents a product in the order along with the price at the time of ordering.
+type OrderItem struct {
+	ID               int64   `json:"id"`
+	OrderID          int64   `json:"order_id"`
+	ProductID        int64   `json:"product_id"`
+	ProductName      string  `json:"product_name,omitempty"`
+	Quantity         int     `json:"quantity"`
+	PriceAtOrder     float64 `json:"price_at_order"`
+	LineTotal        float64 `json:"line_total"`
+	FulfilmentStatus string  `json:"fulfilment_status,omitempty"`
+	TrackingNumber   string  `json:"tracking_number,omitempty"`
+}
+
+// VendorOrderItem represents an order item of the vendor's product along with its fulfilment details.
+type VendorOrderItem struct {
+	ID               int64      `json:"id"`
+	OrderID          int64      `json:"order_id"`
+	OrderStatus      string     `json:"order_status"`
+	ProductID        int64      `json:"product_id"`
+	ProductName      string     `json:"product_name"`
+	Quantity         int        `json:"quantity"`
+	PriceAtOrder     float64    `json:"price_at_order"`
+	FulfilmentStatus string     `json:"fulfilment_status"`
+	TrackingNumber   string     `json:"tracking_number,omitempty"`
+	PackedAt         *time.Time `json:"packed_at,omitempty"`
+	ShippedAt        *time.Time `json:"shipped_at,omitempty"`
+	OrderedAt        time.Time  `json:"ordered_at"`
+}
+
+// VendorFulfilment represents the fulfilment progress of a single vendor's items in an order.
+type VendorFulfilment struct {
+	VendorID     int64  `json:"vendor_id"`
+	TotalItems   int    `json:"total_items"`
+	PackedItems  int    `json:"packed_items"`
+	ShippedItems int    `json:"shipped_items"`
+	Status       string `json:"status"`
+}
+
+// OrderFulfilment represents the fulfilment progress of an order aggregated across vendors.
+type OrderFulfilment struct {
+	OrderID      int64               `json:"order_id"`
+	OrderStatus  string              `json:"order_status"`
+	TotalItems   int                 `json:"total_items"`
+	PackedItems  int                 `json:"packed_items"`
+	ShippedItems int                 `json:"shipped_items"`
+	Status       string              `json:"status"`
+	Vendors      *[]VendorFulfilment `json:"vendors"`
+}
+
+// fulfilmentStatus summarizes the item counts, packed items include the shipped ones.
+func fulfilmentStatus(total, packed, shipped int) string {
+	switch {
+	case total > 0 && shipped == total:
+		return FulfilmentShipped
+	case shipped > 0:
+		return FulfilmentPartiallyShipped
+	case total > 0 && packed == total:
+		return FulfilmentPacked
+	case packed > 0:
+		return FulfilmentPartiallyPacked
+	default:
+		return FulfilmentPending
+	}
+}
+
+// ListVendorOrderItemReq represents the filters for listing the vendor's order items.
+type ListVendorOrderItemReq struct {
+	VendorID int64  `json:"vendor_id,omitempty"`
+	Status   string `json:"status" validate:"omitempty,oneof=pending packed shipped"`
+}
+
+// ListVendorOrderItemRes struct for returning a page of vendor order items
+type ListVendorOrderItemRes struct {
+	Count      int                `json:"count"`
+	Items      *[]VendorOrderItem `json:"items"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+	HasMore    bool               `json:"has_more"`
+}
+
+// FulfilItemReq represents the request payload for marking the vendor's order item packed or shipped.
+type FulfilItemReq struct {
+	ItemID         int64  `json:"item_id,omitempty"`
+	VendorID       int64  `json:"vendor_id,omitempty"`
+	Status         string `json:"status,omitempty" validate:"required,oneof=packed shipped"`
+	TrackingNumber string `json:"tracking_number" validate:"max=100"`
+}
+
+// ShipItemReq represents the request payload for marking the vendor's order item shipped.
+type ShipItemReq struct {
+	TrackingNumber string `json:"tracking_number" validate:"required,max=100"`
+}
+
+// FulfilItemRes represents the response after updating the fulfilment of an order item.
+type FulfilItemRes struct {
+	Item       *VendorOrderItem `json:"item"`
+	Fulfilment *OrderFulfilment `json:"fulfilment"`
+}
+
+// StatusHistory represents a status transition of the order along with the actor.
+type StatusHistory struct {
+	ID         int64     `json:"id"`
+	OrderID    int64     `json:"order_id"`
+	FromStatus string    `json:"from_status,omitempty"`
+	ToStatus   string    `json:"to_status"`
+	ActorID    int64     `json:"actor_id,omitempty"`
+	Note       string    `json:"note,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// OrderDetailRes represents the order along with its items, shipping address and status timeline.
+type OrderDetailRes struct {
+	Order
+	ShippingAddress *address.Address `json:"shipping_address"`
+	SubTotal        float64          `json:"sub_total"`
+	Fulfilment      *OrderFulfilment `json:"fulfilment"`
+	Timeline        *[]StatusHistory `json:"timeline"`
+}
+
+// ListOrderReq represents the filters for listing the user's orders.
+type ListOrderReq struct {
+	UserID int64     `json:"user_id,omitempty"`
+	Status string    `json:"status" validate:"omitempty,oneof=pending paid packed shipped delivered cancelled refunded"`
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+}
+
+// ListOrderRes struct for returning a page of orders
+type ListOrderRes struct {
+	Count      int      `json:"count"`
+	Orders     *[]Order `json:"orders"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+	HasMore    bool     `json:"has_more"`
+}
+
+// UpdateStatusReq represents the request payload for moving an order to a new status.
+type UpdateStatusReq struct {
+	OrderID int64  `json:"order_id,omitempty"`
+	ActorID int64  `json:"actor_id,omitempty"`
+	Status  string `json:"status" validate:"required,oneof=paid packed shipped delivered cancelled refunded"`
+	Note    string `json:"note" validate:"max=255"`
+}
+
+// CheckoutReq represents the request payload for placing an order from the cart.
+type CheckoutReq struct {
+	UserID        int64  `json:"user_id,omitempty"`
+	AddressID     int64  `json:"address_id" validate:"required"`
+	PaymentMethod string `json:"payment_method" validate:"required,oneof=cod card upi"`
+}
+
+// CheckoutErrorRes represents the response returned when the checkout fails due to stock.
+type CheckoutErrorRes struct {
+	Success   bool   `json:"success"`
+	Message   string `json:"message"`
+	Code      string `json:"code"`
+	ProductID int64  `json:"product_id"`
+	Requested int    `json:"requested"`
+	Available int    `json:"available"`
+}
+
+// CheckoutProblemRes represents the problem details returned when the checkout fails due to stock.
+type CheckoutProblemRes struct {
+	utils.ProblemRes
+	ProductID int64 `json:"product_id"`
+	Requested int   `json:"requested"`
+	Available int   `json:"available"`
+}
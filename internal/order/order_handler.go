@@ -0,0 +1,479 @@
+package order
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aslam-ep/go-e-commerce/internal/pagination"
+	"github.com/aslam-ep/go-e-commerce/router/middleware"
+	"github.com/aslam-ep/go-e-commerce/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+// Handler handles HTTP requests related to user orders.
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new instance of the Handler with the provided order service.
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+func (h *Handler) getIDsFromParam(r *http.Request) (int, int, error) {
+	userIDStr := chi.URLParam(r, "user_id")
+	userID, err := strconv.Atoi(userIDStr)
+	if err != nil {
+		return -1, -1, err
+	}
+
+	orderIDStr := chi.URLParam(r, "order_id")
+	orderID, err := strconv.Atoi(orderIDStr)
+	if err != nil {
+		return -1, -1, err
+	}
+
+	return orderID, userID, nil
+}
+
+// writeServiceError writes the error response of the service error, stock errors carry the product details
+func (h *Handler) writeServiceError(w http.ResponseWriter, r *http.Request, err error) {
+	var stockErr *InsufficientStockError
+	if errors.As(err, &stockErr) {
+		if utils.WantsProblem(r) {
+			utils.WriteProblem(w, http.StatusConflict, &CheckoutProblemRes{
+				ProblemRes: *utils.NewProblem(r, http.StatusConflict, "insufficient_stock", stockErr.Error()),
+				ProductID:  stockErr.ProductID,
+				Requested:  stockErr.Requested,
+				Available:  stockErr.Available,
+			})
+			return
+		}
+
+		utils.WriteResponse(w, http.StatusConflict, &CheckoutErrorRes{
+			Success:   false,
+			Message:   stockErr.Error(),
+			Code:      "insufficient_stock",
+			ProductID: stockErr.ProductID,
+			Requested: stockErr.Requested,
+			Available: stockErr.Available,
+		})
+		return
+	}
+
+	utils.WriteError(w, r, err)
+}
+
+// Checkout godoc
+// @Summary      Checkout cart
+// @Description  Place an order from the authenticated user's cart to the given address
+// @Tags         Order
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        user_id  path      int          true  "User ID"
+// @Param        body     body      CheckoutReq  true  "Checkout request"
+// @Success      200      {object}  Order
+// @Failure      400      {object}  utils.MessageRes
+// @Failure      401      {object}  utils.MessageRes
+// @Failure      403      {object}  utils.MessageRes
+// @Failure      409      {object}  CheckoutErrorRes
+// @Failure      500      {object}  utils.MessageRes
+// @Router       /users/{user_id}/orders/checkout [post]
+func (h *Handler) Checkout(w http.ResponseWriter, r *http.Request) {
+	var checkoutReq CheckoutReq
+	if err := utils.ReadFromRequest(r, &checkoutReq); err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userIDStr := chi.URLParam(r, "user_id")
+	userID, err := strconv.Atoi(userIDStr)
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	checkoutReq.UserID = int64(userID)
+
+	if err := utils.Validate.Struct(checkoutReq); err != nil {
+		utils.WriteValidationError(w, r, err)
+		return
+	}
+
+	res, err := h.service.Checkout(r.Context(), &checkoutReq)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// parseListOrderReq reads the filters from the query parameters
+func (h *Handler) parseListOrderReq(r *http.Request) (*ListOrderReq, error) {
+	query := r.URL.Query()
+	req := &ListOrderReq{
+		Status: query.Get("status"),
+	}
+
+	if from := query.Get("from"); from != "" {
+		value, err := time.Parse(time.DateOnly, from)
+		if err != nil {
+			return nil, errors.New("from must be a date in YYYY-MM-DD format")
+		}
+		req.From = value
+	}
+
+	// The to date is inclusive, so filter up to the start of the next day
+	if to := query.Get("to"); to != "" {
+		value, err := time.Parse(time.DateOnly, to)
+		if err != nil {
+			return nil, errors.New("to must be a date in YYYY-MM-DD format")
+		}
+		req.To = value.AddDate(0, 0, 1)
+	}
+
+	return req, nil
+}
+
+// ListOrders godoc
+// @Summary      List orders
+// @Description  Get the authenticated user's orders matching the filters page by page, pass the next_cursor of a page to get the next one
+// @Tags         Order
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        user_id  path      int     true   "User ID"
+// @Param        status   query     string  false  "Order status"
+// @Param        from     query     string  false  "Placed on or after date (YYYY-MM-DD)"
+// @Param        to       query     string  false  "Placed on or before date (YYYY-MM-DD)"
+// @Param        cursor   query     string  false  "Cursor of the page to get"
+// @Param        limit    query     int     false  "Orders per page, max 100"
+// @Param        sort     query     string  false  "Sort, one of -created_at, created_at, -id, id, newest first by default"
+// @Success      200      {object}  ListOrderRes
+// @Failure      400      {object}  utils.MessageRes
+// @Failure      401      {object}  utils.MessageRes
+// @Failure      500      {object}  utils.MessageRes
+// @Router       /users/{user_id}/orders/ [get]
+func (h *Handler) ListOrders(w http.ResponseWriter, r *http.Request) {
+	listReq, err := h.parseListOrderReq(r)
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userIDStr := chi.URLParam(r, "user_id")
+	userID, err := strconv.Atoi(userIDStr)
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	listReq.UserID = int64(userID)
+
+	if err := utils.Validate.Struct(listReq); err != nil {
+		utils.WriteValidationError(w, r, err)
+		return
+	}
+
+	params, err := pagination.ParamsFromRequest(r)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	res, err := h.service.ListOrders(r.Context(), listReq, params)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// GetOrderByID godoc
+// @Summary      Get order by ID
+// @Description  Get a specific order of the authenticated user with items, shipping address, totals and status timeline
+// @Tags         Order
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        user_id   path      int  true  "User ID"
+// @Param        order_id  path      int  true  "Order ID"
+// @Success      200       {object}  OrderDetailRes
+// @Failure      400       {object}  utils.MessageRes
+// @Failure      401       {object}  utils.MessageRes
+// @Failure      404       {object}  utils.MessageRes
+// @Router       /users/{user_id}/orders/{order_id} [get]
+func (h *Handler) GetOrderByID(w http.ResponseWriter, r *http.Request) {
+	orderID, userID, err := h.getIDsFromParam(r)
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.GetOrderByID(r.Context(), orderID, userID)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// CancelOrder godoc
+// @Summary      Cancel order
+// @Description  Cancel an order of the authenticated user which hasn't been packed yet
+// @Tags         Order
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        user_id   path      int  true  "User ID"
+// @Param        order_id  path      int  true  "Order ID"
+// @Success      200       {object}  Order
+// @Failure      400       {object}  utils.MessageRes
+// @Failure      401       {object}  utils.MessageRes
+// @Failure      404       {object}  utils.MessageRes
+// @Failure      409       {object}  utils.MessageRes
+// @Router       /users/{user_id}/orders/{order_id}/cancel [put]
+func (h *Handler) CancelOrder(w http.ResponseWriter, r *http.Request) {
+	orderID, userID, err := h.getIDsFromParam(r)
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.CancelOrder(r.Context(), orderID, userID)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// UpdateOrderStatus godoc
+// @Summary      Update order status
+// @Description  Move an order to a new status when the transition is allowed, recording the admin as the actor
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        order_id  path      int              true  "Order ID"
+// @Param        body      body      UpdateStatusReq  true  "Order status update request"
+// @Success      200       {object}  Order
+// @Failure      400       {object}  utils.MessageRes
+// @Failure      401       {object}  utils.MessageRes
+// @Failure      403       {object}  utils.MessageRes
+// @Failure      404       {object}  utils.MessageRes
+// @Failure      409       {object}  utils.MessageRes
+// @Router       /admin/orders/{order_id}/status [put]
+func (h *Handler) UpdateOrderStatus(w http.ResponseWriter, r *http.Request) {
+	var statusReq UpdateStatusReq
+	if err := utils.ReadFromRequest(r, &statusReq); err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	orderIDStr := chi.URLParam(r, "order_id")
+	orderID, err := strconv.Atoi(orderIDStr)
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	actorID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	statusReq.OrderID = int64(orderID)
+	statusReq.ActorID = int64(actorID)
+
+	if err := utils.Validate.Struct(statusReq); err != nil {
+		utils.WriteValidationError(w, r, err)
+		return
+	}
+
+	res, err := h.service.UpdateOrderStatus(r.Context(), &statusReq)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// ListVendorOrderItems godoc
+// @Summary      List vendor order items
+// @Description  Get the order items referencing the authenticated vendor's products page by page, pass the next_cursor of a page to get the next one
+// @Tags         Vendor
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        status  query     string  false  "Fulfilment status"
+// @Param        cursor  query     string  false  "Cursor of the page to get"
+// @Param        limit   query     int     false  "Items per page, max 100"
+// @Param        sort    query     string  false  "Sort by the order time, one of -created_at, created_at, -id, id, newest first by default"
+// @Success      200     {object}  ListVendorOrderItemRes
+// @Failure      400     {object}  utils.MessageRes
+// @Failure      401     {object}  utils.MessageRes
+// @Failure      403     {object}  utils.MessageRes
+// @Router       /vendor/order-items/ [get]
+func (h *Handler) ListVendorOrderItems(w http.ResponseWriter, r *http.Request) {
+	params, err := pagination.ParamsFromRequest(r)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	vendorID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	listReq := &ListVendorOrderItemReq{
+		VendorID: int64(vendorID),
+		Status:   r.URL.Query().Get("status"),
+	}
+
+	if err := utils.Validate.Struct(listReq); err != nil {
+		utils.WriteValidationError(w, r, err)
+		return
+	}
+
+	res, err := h.service.ListVendorOrderItems(r.Context(), listReq, params)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// fulfilOrderItem updates the fulfilment of the vendor's order item from the URL parameters
+func (h *Handler) fulfilOrderItem(w http.ResponseWriter, r *http.Request, fulfilReq *FulfilItemReq) {
+	itemIDStr := chi.URLParam(r, "item_id")
+	itemID, err := strconv.Atoi(itemIDStr)
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	vendorID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	fulfilReq.ItemID = int64(itemID)
+	fulfilReq.VendorID = int64(vendorID)
+
+	if err := utils.Validate.Struct(fulfilReq); err != nil {
+		utils.WriteValidationError(w, r, err)
+		return
+	}
+
+	res, err := h.service.FulfilOrderItem(r.Context(), fulfilReq)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// PackOrderItem godoc
+// @Summary      Pack order item
+// @Description  Mark an order item of the authenticated vendor's product as packed
+// @Tags         Vendor
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        item_id  path      int  true  "Order item ID"
+// @Success      200      {object}  FulfilItemRes
+// @Failure      400      {object}  utils.MessageRes
+// @Failure      401      {object}  utils.MessageRes
+// @Failure      403      {object}  utils.MessageRes
+// @Failure      404      {object}  utils.MessageRes
+// @Failure      409      {object}  utils.MessageRes
+// @Router       /vendor/order-items/{item_id}/pack [put]
+func (h *Handler) PackOrderItem(w http.ResponseWriter, r *http.Request) {
+	h.fulfilOrderItem(w, r, &FulfilItemReq{Status: FulfilmentPacked})
+}
+
+// ShipOrderItem godoc
+// @Summary      Ship order item
+// @Description  Mark an order item of the authenticated vendor's product as shipped with the tracking number
+// @Tags         Vendor
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        item_id  path      int          true  "Order item ID"
+// @Param        body     body      ShipItemReq  true  "Ship order item request"
+// @Success      200      {object}  FulfilItemRes
+// @Failure      400      {object}  utils.MessageRes
+// @Failure      401      {object}  utils.MessageRes
+// @Failure      403      {object}  utils.MessageRes
+// @Failure      404      {object}  utils.MessageRes
+// @Failure      409      {object}  utils.MessageRes
+// @Router       /vendor/order-items/{item_id}/ship [put]
+func (h *Handler) ShipOrderItem(w http.ResponseWriter, r *http.Request) {
+	var shipReq ShipItemReq
+	if err := utils.ReadFromRequest(r, &shipReq); err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := utils.Validate.Struct(shipReq); err != nil {
+		utils.WriteValidationError(w, r, err)
+		return
+	}
+
+	h.fulfilOrderItem(w, r, &FulfilItemReq{
+		Status:         FulfilmentShipped,
+		TrackingNumber: shipReq.TrackingNumber,
+	})
+}
+
+// GetVendorOrderFulfilment godoc
+// @Summary      Get order fulfilment
+// @Description  Get the fulfilment progress per vendor of an order containing the authenticated vendor's products
+// @Tags         Vendor
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        order_id  path      int  true  "Order ID"
+// @Success      200       {object}  OrderFulfilment
+// @Failure      400       {object}  utils.MessageRes
+// @Failure      401       {object}  utils.MessageRes
+// @Failure      403       {object}  utils.MessageRes
+// @Failure      404       {object}  utils.MessageRes
+// @Router       /vendor/orders/{order_id}/fulfilment [get]
+func (h *Handler) GetVendorOrderFulfilment(w http.ResponseWriter, r *http.Request) {
+	orderIDStr := chi.URLParam(r, "order_id")
+	orderID, err := strconv.Atoi(orderIDStr)
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	vendorID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	res, err := h.service.GetVendorOrderFulfilment(r.Context(), orderID, vendorID)
+	if err != nil {
+		h.writeServiceError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
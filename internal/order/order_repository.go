@@ -0,0 +1,623 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/aslam-ep/go-e-commerce/apperrors"
+	"github.com/aslam-ep/go-e-commerce/internal/pagination"
+)
+
+// Repository interface for order repository
+type Repository interface {
+	// Checkout Place the order from the user's cart items to the user's address, updating the stock and removing
+	// the ordered items from the cart
+	Checkout(ctx context.Context, order *Order) (*Order, error)
+
+	// GetAll Get the page of the user's orders matching the filters
+	GetAll(ctx context.Context, req *ListOrderReq, page *pagination.Page) (*[]Order, error)
+
+	// GetItems Get the items of the given order along with the product names
+	GetItems(ctx context.Context, orderID int) (*[]OrderItem, error)
+
+	// GetByID Get order by the given ID
+	GetByID(ctx context.Context, id int) (*Order, error)
+
+	// UpdateStatus Move the order to the new status and record the transition in the history
+	UpdateStatus(ctx context.Context, history *StatusHistory) error
+
+	// GetStatusHistory Get the status transitions of the given order, oldest first
+	GetStatusHistory(ctx context.Context, orderID int) (*[]StatusHistory, error)
+
+	// GetVendorItems Get the page of the order items of the vendor's products matching the filters
+	GetVendorItems(ctx context.Context, req *ListVendorOrderItemReq, page *pagination.Page) (*[]VendorOrderItem, error)
+
+	// GetVendorItem Get the order item by the given ID when it references the vendor's product
+	GetVendorItem(ctx context.Context, id int, vendorID int) (*VendorOrderItem, error)
+
+	// UpdateItemFulfilment Update the vendor's order item fulfilment and move the order along once every item caught up
+	UpdateItemFulfilment(ctx context.Context, req *FulfilItemReq) (*OrderFulfilment, error)
+
+	// GetFulfilment Get the fulfilment progress of the given order aggregated per vendor
+	GetFulfilment(ctx context.Context, orderID int) (*OrderFulfilment, error)
+}
+
+// querier is satisfied by both *sql.DB and *sql.Tx
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+// NewRepository initialize and returns order repository
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+// nullableID converts the id to a sql.NullInt64, treating zero as NULL
+func nullableID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id > 0}
+}
+
+// nullableTime converts the time to a sql.NullTime, treating zero time as NULL
+func nullableTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// roundAmount rounds the amount to two decimal places
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+func (r *repository) Checkout(ctx context.Context, order *Order) (*Order, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Rollback is a no-op once the transaction is committed
+	defer tx.Rollback()
+
+	// Make sure the shipping address belongs to the user, locking it so it can't be deleted before the order references it
+	var addressID int64
+	addressQuery := `SELECT id FROM addresses WHERE id = $1 AND user_id = $2 FOR SHARE;`
+	if err := tx.QueryRowContext(ctx, addressQuery, order.AddressID, order.UserID).Scan(&addressID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidAddress
+		}
+		return nil, err
+	}
+
+	// Lock the products in the cart, ordered by id so concurrent checkouts can't deadlock
+	cartQuery := `SELECT ci.product_id, ci.quantity, p.price, p.stock_count
+		FROM cart_items ci
+		JOIN products p ON p.id = ci.product_id
+		WHERE ci.user_id = $1 AND p.is_deleted = false
+		ORDER BY p.id
+		FOR UPDATE OF p;`
+
+	rows, err := tx.QueryContext(ctx, cartQuery, order.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []OrderItem
+	var total float64
+	for rows.Next() {
+		var item OrderItem
+		var stockCount int
+		if err := rows.Scan(
+			&item.ProductID,
+			&item.Quantity,
+			&item.PriceAtOrder,
+			&stockCount,
+		); err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		if item.Quantity > stockCount {
+			rows.Close()
+			return nil, &InsufficientStockError{
+				ProductID: item.ProductID,
+				Requested: item.Quantity,
+				Available: stockCount,
+			}
+		}
+
+		item.LineTotal = roundAmount(item.PriceAtOrder * float64(item.Quantity))
+		total += item.LineTotal
+		items = append(items, item)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(items) == 0 {
+		return nil, ErrEmptyCart
+	}
+
+	stockQuery := `UPDATE products SET stock_count = stock_count - $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2;`
+	for _, item := range items {
+		if _, err := tx.ExecContext(ctx, stockQuery, item.Quantity, item.ProductID); err != nil {
+			return nil, err
+		}
+	}
+
+	order.TotalAmount = roundAmount(total)
+	orderQuery := `INSERT INTO orderes(user_id, address_id, total_amount, payment_method) VALUES($1, $2, $3, $4) RETURNING id, status, created_at, updated_at;`
+	err = tx.QueryRowContext(ctx, orderQuery,
+		order.UserID,
+		order.AddressID,
+		order.TotalAmount,
+		order.PaymentMethod,
+	).Scan(
+		&order.ID,
+		&order.Status,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	historyQuery := `INSERT INTO order_status_history(order_id, to_status, actor_id, note) VALUES($1, $2, $3, $4);`
+	if _, err := tx.ExecContext(ctx, historyQuery, order.ID, order.Status, nullableID(order.UserID), "Order placed"); err != nil {
+		return nil, err
+	}
+
+	itemQuery := `INSERT INTO order_items(order_id, product_id, quantity, price_at_order) VALUES($1, $2, $3, $4) RETURNING id;`
+	for i := range items {
+		items[i].OrderID = order.ID
+		if err := tx.QueryRowContext(ctx, itemQuery,
+			items[i].OrderID,
+			items[i].ProductID,
+			items[i].Quantity,
+			items[i].PriceAtOrder,
+		).Scan(&items[i].ID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Only the ordered items leave the cart, items of products that are no longer available weren't part of the order
+	removeCartItemQuery := `DELETE FROM cart_items WHERE user_id = $1 AND product_id = $2;`
+	for _, item := range items {
+		if _, err := tx.ExecContext(ctx, removeCartItemQuery, order.UserID, item.ProductID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	order.Items = &items
+
+	return order, nil
+}
+
+func (r *repository) GetAll(ctx context.Context, req *ListOrderReq, page *pagination.Page) (*[]Order, error) {
+	// The To filter is exclusive so callers pass the start of the following day
+	selectQuery, args := page.Apply(
+		`SELECT id, user_id, address_id, total_amount, status, COALESCE(payment_method, ''), created_at, updated_at
+		FROM orderes
+		WHERE user_id = $1 AND is_deleted = false
+			AND ($2 = '' OR status = $2)
+			AND ($3::timestamptz IS NULL OR created_at >= $3)
+			AND ($4::timestamptz IS NULL OR created_at < $4)`,
+		[]any{req.UserID, req.Status, nullableTime(req.From), nullableTime(req.To)},
+	)
+
+	rows, err := r.db.QueryContext(ctx, selectQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := []Order{}
+	for rows.Next() {
+		var order Order
+		if err := rows.Scan(
+			&order.ID,
+			&order.UserID,
+			&order.AddressID,
+			&order.TotalAmount,
+			&order.Status,
+			&order.PaymentMethod,
+			&order.CreatedAt,
+			&order.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		orders = append(orders, order)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &orders, nil
+}
+
+func (r *repository) GetItems(ctx context.Context, orderID int) (*[]OrderItem, error) {
+	selectByOrderIDQuery := `SELECT oi.id, oi.order_id, oi.product_id, p.name, oi.quantity, oi.price_at_order, oi.fulfilment_status, COALESCE(oi.tracking_number, '')
+		FROM order_items oi
+		JOIN products p ON p.id = oi.product_id
+		WHERE oi.order_id = $1 AND oi.is_deleted = false
+		ORDER BY oi.id;`
+
+	rows, err := r.db.QueryContext(ctx, selectByOrderIDQuery, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []OrderItem{}
+	for rows.Next() {
+		var item OrderItem
+		if err := rows.Scan(
+			&item.ID,
+			&item.OrderID,
+			&item.ProductID,
+			&item.ProductName,
+			&item.Quantity,
+			&item.PriceAtOrder,
+			&item.FulfilmentStatus,
+			&item.TrackingNumber,
+		); err != nil {
+			return nil, err
+		}
+
+		item.LineTotal = roundAmount(item.PriceAtOrder * float64(item.Quantity))
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &items, nil
+}
+
+func (r *repository) GetByID(ctx context.Context, id int) (*Order, error) {
+	var order Order
+	selectByIDQuery := `SELECT id, user_id, address_id, total_amount, status, COALESCE(payment_method, ''), created_at, updated_at FROM orderes WHERE id = $1 AND is_deleted = false;`
+
+	err := r.db.QueryRowContext(ctx, selectByIDQuery, id).Scan(
+		&order.ID,
+		&order.UserID,
+		&order.AddressID,
+		&order.TotalAmount,
+		&order.Status,
+		&order.PaymentMethod,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, apperrors.FromDB(err, "order")
+	}
+
+	return &order, nil
+}
+
+func (r *repository) UpdateStatus(ctx context.Context, history *StatusHistory) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// Rollback is a no-op once the transaction is committed
+	defer tx.Rollback()
+
+	if err := r.moveStatus(ctx, tx, history); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// moveStatus updates the order status within the transaction and records the transition
+func (r *repository) moveStatus(ctx context.Context, tx *sql.Tx, history *StatusHistory) error {
+	// Only move the order when it is still in the expected status
+	updateQuery := `UPDATE orderes SET status = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2 AND status = $3;`
+	result, err := tx.ExecContext(ctx, updateQuery, history.ToStatus, history.OrderID, history.FromStatus)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrInvalidTransition
+	}
+
+	// Cancelled orders return the reserved stock to the products
+	if history.ToStatus == StatusCancelled {
+		restockQuery := `UPDATE products p SET stock_count = p.stock_count + oi.quantity, updated_at = CURRENT_TIMESTAMP FROM order_items oi WHERE oi.order_id = $1 AND p.id = oi.product_id;`
+		if _, err := tx.ExecContext(ctx, restockQuery, history.OrderID); err != nil {
+			return err
+		}
+	}
+
+	historyQuery := `INSERT INTO order_status_history(order_id, from_status, to_status, actor_id, note) VALUES($1, $2, $3, $4, $5) RETURNING id, created_at;`
+
+	return tx.QueryRowContext(ctx, historyQuery,
+		history.OrderID,
+		history.FromStatus,
+		history.ToStatus,
+		nullableID(history.ActorID),
+		history.Note,
+	).Scan(
+		&history.ID,
+		&history.CreatedAt,
+	)
+}
+
+func (r *repository) GetStatusHistory(ctx context.Context, orderID int) (*[]StatusHistory, error) {
+	selectByOrderIDQuery := `SELECT id, order_id, COALESCE(from_status, ''), to_status, actor_id, COALESCE(note, ''), created_at FROM order_status_history WHERE order_id = $1 ORDER BY created_at, id;`
+
+	rows, err := r.db.QueryContext(ctx, selectByOrderIDQuery, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	timeline := []StatusHistory{}
+	for rows.Next() {
+		var history StatusHistory
+		var actorID sql.NullInt64
+		if err := rows.Scan(
+			&history.ID,
+			&history.OrderID,
+			&history.FromStatus,
+			&history.ToStatus,
+			&actorID,
+			&history.Note,
+			&history.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		history.ActorID = actorID.Int64
+		timeline = append(timeline, history)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &timeline, nil
+}
+
+// vendorItemColumns lists the columns scanned by scanVendorItem
+const vendorItemColumns = `oi.id, oi.order_id, o.status, oi.product_id, p.name, oi.quantity, oi.price_at_order, oi.fulfilment_status, COALESCE(oi.tracking_number, ''), oi.packed_at, oi.shipped_at, o.created_at`
+
+// scanVendorItem scans a row selected with vendorItemColumns
+func scanVendorItem(row interface{ Scan(dest ...any) error }) (*VendorOrderItem, error) {
+	var item VendorOrderItem
+	var packedAt, shippedAt sql.NullTime
+	if err := row.Scan(
+		&item.ID,
+		&item.OrderID,
+		&item.OrderStatus,
+		&item.ProductID,
+		&item.ProductName,
+		&item.Quantity,
+		&item.PriceAtOrder,
+		&item.FulfilmentStatus,
+		&item.TrackingNumber,
+		&packedAt,
+		&shippedAt,
+		&item.OrderedAt,
+	); err != nil {
+		return nil, apperrors.FromDB(err, "order item")
+	}
+
+	if packedAt.Valid {
+		item.PackedAt = &packedAt.Time
+	}
+	if shippedAt.Valid {
+		item.ShippedAt = &shippedAt.Time
+	}
+
+	return &item, nil
+}
+
+func (r *repository) GetVendorItems(ctx context.Context, req *ListVendorOrderItemReq, page *pagination.Page) (*[]VendorOrderItem, error) {
+	selectQuery, args := page.ApplyColumns(
+		`SELECT `+vendorItemColumns+`
+		FROM order_items oi
+		JOIN products p ON p.id = oi.product_id
+		JOIN orderes o ON o.id = oi.order_id
+		WHERE p.vendor_id = $1 AND oi.is_deleted = false AND o.is_deleted = false
+			AND ($2 = '' OR oi.fulfilment_status = $2)`,
+		[]any{req.VendorID, req.Status},
+		pagination.Columns{pagination.SortByID: "oi.id", pagination.SortByCreatedAt: "o.created_at"},
+	)
+
+	rows, err := r.db.QueryContext(ctx, selectQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []VendorOrderItem{}
+	for rows.Next() {
+		item, err := scanVendorItem(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, *item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &items, nil
+}
+
+func (r *repository) GetVendorItem(ctx context.Context, id int, vendorID int) (*VendorOrderItem, error) {
+	selectByIDQuery := `SELECT ` + vendorItemColumns + `
+		FROM order_items oi
+		JOIN products p ON p.id = oi.product_id
+		JOIN orderes o ON o.id = oi.order_id
+		WHERE oi.id = $1 AND p.vendor_id = $2 AND oi.is_deleted = false AND o.is_deleted = false;`
+
+	return scanVendorItem(r.db.QueryRowContext(ctx, selectByIDQuery, id, vendorID))
+}
+
+func (r *repository) UpdateItemFulfilment(ctx context.Context, req *FulfilItemReq) (*OrderFulfilment, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Rollback is a no-op once the transaction is committed
+	defer tx.Rollback()
+
+	// Lock the item and its order so concurrent vendors aggregate on the latest state
+	var orderID int64
+	var itemStatus, orderStatus string
+	lockQuery := `SELECT oi.order_id, oi.fulfilment_status, o.status
+		FROM order_items oi
+		JOIN products p ON p.id = oi.product_id
+		JOIN orderes o ON o.id = oi.order_id
+		WHERE oi.id = $1 AND p.vendor_id = $2 AND oi.is_deleted = false AND o.is_deleted = false
+		FOR UPDATE OF oi, o;`
+	err = tx.QueryRowContext(ctx, lockQuery, req.ItemID, req.VendorID).Scan(&orderID, &itemStatus, &orderStatus)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrOrderItemNotFound
+		}
+		return nil, err
+	}
+
+	// Items can only be fulfilled once the order is paid and before it has fully shipped
+	if orderStatus != StatusPaid && orderStatus != StatusPacked {
+		return nil, fmt.Errorf("%w: %s order can't be fulfilled", ErrInvalidTransition, orderStatus)
+	}
+	if itemStatus == FulfilmentShipped || itemStatus == req.Status {
+		return nil, fmt.Errorf("%w: item is already %s", ErrInvalidTransition, itemStatus)
+	}
+
+	updateQuery := `UPDATE order_items SET
+			fulfilment_status = $1,
+			tracking_number = COALESCE(NULLIF($2, ''), tracking_number),
+			packed_at = COALESCE(packed_at, CURRENT_TIMESTAMP),
+			shipped_at = CASE WHEN $1 = 'shipped' THEN CURRENT_TIMESTAMP ELSE shipped_at END
+		WHERE id = $3;`
+	if _, err := tx.ExecContext(ctx, updateQuery, req.Status, req.TrackingNumber, req.ItemID); err != nil {
+		return nil, err
+	}
+
+	fulfilment, err := getFulfilment(ctx, tx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Move the order along once every vendor has packed or shipped all of the items
+	var nextStatuses []string
+	if orderStatus == StatusPaid && fulfilment.PackedItems == fulfilment.TotalItems {
+		nextStatuses = append(nextStatuses, StatusPacked)
+	}
+	if fulfilment.ShippedItems == fulfilment.TotalItems {
+		nextStatuses = append(nextStatuses, StatusShipped)
+	}
+
+	for _, status := range nextStatuses {
+		history := &StatusHistory{
+			OrderID:    orderID,
+			FromStatus: orderStatus,
+			ToStatus:   status,
+			ActorID:    req.VendorID,
+			Note:       fmt.Sprintf("All items %s", status),
+		}
+		if err := r.moveStatus(ctx, tx, history); err != nil {
+			return nil, err
+		}
+		orderStatus = status
+	}
+	fulfilment.OrderStatus = orderStatus
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return fulfilment, nil
+}
+
+func (r *repository) GetFulfilment(ctx context.Context, orderID int) (*OrderFulfilment, error) {
+	fulfilment, err := getFulfilment(ctx, r.db, int64(orderID))
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := r.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	fulfilment.OrderStatus = order.Status
+
+	return fulfilment, nil
+}
+
+// getFulfilment aggregates the order item fulfilment statuses per vendor
+func getFulfilment(ctx context.Context, q querier, orderID int64) (*OrderFulfilment, error) {
+	aggregateQuery := `SELECT p.vendor_id,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE oi.fulfilment_status IN ('packed', 'shipped')),
+			COUNT(*) FILTER (WHERE oi.fulfilment_status = 'shipped')
+		FROM order_items oi
+		JOIN products p ON p.id = oi.product_id
+		WHERE oi.order_id = $1 AND oi.is_deleted = false
+		GROUP BY p.vendor_id
+		ORDER BY p.vendor_id;`
+
+	rows, err := q.QueryContext(ctx, aggregateQuery, orderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fulfilment := &OrderFulfilment{OrderID: orderID}
+	vendors := []VendorFulfilment{}
+	for rows.Next() {
+		var vendor VendorFulfilment
+		if err := rows.Scan(
+			&vendor.VendorID,
+			&vendor.TotalItems,
+			&vendor.PackedItems,
+			&vendor.ShippedItems,
+		); err != nil {
+			return nil, err
+		}
+
+		vendor.Status = fulfilmentStatus(vendor.TotalItems, vendor.PackedItems, vendor.ShippedItems)
+		fulfilment.TotalItems += vendor.TotalItems
+		fulfilment.PackedItems += vendor.PackedItems
+		fulfilment.ShippedItems += vendor.ShippedItems
+		vendors = append(vendors, vendor)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	fulfilment.Status = fulfilmentStatus(fulfilment.TotalItems, fulfilment.PackedItems, fulfilment.ShippedItems)
+	fulfilment.Vendors = &vendors
+
+	return fulfilment, nil
+}
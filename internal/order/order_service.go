@@ -0,0 +1,313 @@
+package order
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aslam-ep/go-e-commerce/config"
+	"github.com/aslam-ep/go-e-commerce/internal/address"
+	"github.com/aslam-ep/go-e-commerce/internal/pagination"
+	"github.com/aslam-ep/go-e-commerce/internal/user"
+)
+
+// ListSorts are the sorts of the order and vendor order item lists, newest first by default
+var ListSorts = []string{"-" + pagination.SortByCreatedAt, pagination.SortByCreatedAt, "-" + pagination.SortByID, pagination.SortByID}
+
+// Service interface defines the methods required for order services.
+type Service interface {
+	// Checkout Places an order from the user's cart to the given address and returns the created order.
+	Checkout(c context.Context, req *CheckoutReq) (*Order, error)
+
+	// ListOrders Get a page of the user's orders matching the filters.
+	ListOrders(c context.Context, req *ListOrderReq, params pagination.Params) (*ListOrderRes, error)
+
+	// GetOrderByID Get the user's order by ID along with its items, shipping address and status timeline.
+	GetOrderByID(c context.Context, id int, userID int) (*OrderDetailRes, error)
+
+	// CancelOrder Cancels the user's order when it hasn't been packed yet and returns the updated order.
+	CancelOrder(c context.Context, id int, userID int) (*Order, error)
+
+	// UpdateOrderStatus Moves the order to the requested status when the transition is allowed.
+	UpdateOrderStatus(c context.Context, req *UpdateStatusReq) (*Order, error)
+
+	// ListVendorOrderItems Get a page of the order items referencing the vendor's products.
+	ListVendorOrderItems(c context.Context, req *ListVendorOrderItemReq, params pagination.Params) (*ListVendorOrderItemRes, error)
+
+	// FulfilOrderItem Marks the vendor's order item packed or shipped and returns the order fulfilment progress.
+	FulfilOrderItem(c context.Context, req *FulfilItemReq) (*FulfilItemRes, error)
+
+	// GetVendorOrderFulfilment Get the fulfilment progress of an order containing the vendor's products.
+	GetVendorOrderFulfilment(c context.Context, orderID int, vendorID int) (*OrderFulfilment, error)
+}
+
+type service struct {
+	repository  Repository
+	addressRepo address.Repository
+	userRepo    user.Repository
+	timeout     time.Duration
+}
+
+// NewService creates a new instance of the order service.
+func NewService(orderRepo Repository, addressRepo address.Repository, userRepo user.Repository) Service {
+	return &service{
+		repository:  orderRepo,
+		addressRepo: addressRepo,
+		userRepo:    userRepo,
+		timeout:     time.Duration(config.AppConfig.DBTimeout) * time.Second,
+	}
+}
+
+func (s *service) Checkout(c context.Context, req *CheckoutReq) (*Order, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	if !config.AppConfig.AllowUnverifiedCheckout {
+		u, err := s.userRepo.GetByID(ctx, int(req.UserID))
+		if err != nil {
+			return nil, err
+		}
+		if !u.IsVerified() {
+			return nil, ErrUnverifiedUser
+		}
+	}
+
+	o := &Order{
+		UserID:        req.UserID,
+		AddressID:     req.AddressID,
+		PaymentMethod: req.PaymentMethod,
+	}
+
+	order, err := s.repository.Checkout(ctx, o)
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// getUserOrder returns the order when it belongs to the given user
+func (s *service) getUserOrder(ctx context.Context, id int, userID int) (*Order, error) {
+	order, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, err
+	}
+
+	if order.UserID != int64(userID) {
+		return nil, ErrOrderNotFound
+	}
+
+	return order, nil
+}
+
+// transition moves the order to the new status after validating it against the state machine
+func (s *service) transition(ctx context.Context, order *Order, history *StatusHistory) (*Order, error) {
+	if !CanTransition(order.Status, history.ToStatus) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidTransition, order.Status, history.ToStatus)
+	}
+
+	history.OrderID = order.ID
+	history.FromStatus = order.Status
+
+	if err := s.repository.UpdateStatus(ctx, history); err != nil {
+		return nil, err
+	}
+
+	order.Status = history.ToStatus
+	order.UpdatedAt = history.CreatedAt
+
+	return order, nil
+}
+
+func (s *service) ListOrders(c context.Context, req *ListOrderReq, params pagination.Params) (*ListOrderRes, error) {
+	page, err := pagination.NewPage(params, ListSorts...)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	orders, err := s.repository.GetAll(ctx, req, page)
+	if err != nil {
+		return nil, err
+	}
+
+	pageOrders, info := pagination.Paginate(*orders, page, func(o Order) pagination.Key {
+		return pagination.Key{ID: o.ID, CreatedAt: o.CreatedAt}
+	})
+
+	res := &ListOrderRes{
+		Count:      len(pageOrders),
+		Orders:     &pageOrders,
+		NextCursor: info.NextCursor,
+		HasMore:    info.HasMore,
+	}
+
+	return res, nil
+}
+
+func (s *service) GetOrderByID(c context.Context, id int, userID int) (*OrderDetailRes, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	order, err := s.getUserOrder(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.repository.GetItems(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	order.Items = items
+
+	var subTotal float64
+	for _, item := range *items {
+		subTotal += item.LineTotal
+	}
+
+	// The shipping address could have been removed by the user after ordering
+	shippingAddress, err := s.addressRepo.GetByID(ctx, int(order.AddressID), userID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	fulfilment, err := s.repository.GetFulfilment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	timeline, err := s.repository.GetStatusHistory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &OrderDetailRes{
+		Order:           *order,
+		ShippingAddress: shippingAddress,
+		SubTotal:        roundAmount(subTotal),
+		Fulfilment:      fulfilment,
+		Timeline:        timeline,
+	}
+
+	return res, nil
+}
+
+func (s *service) CancelOrder(c context.Context, id int, userID int) (*Order, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	order, err := s.getUserOrder(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Customers can only cancel the order before it is packed
+	if order.Status != StatusPending && order.Status != StatusPaid {
+		return nil, fmt.Errorf("%w: %s order can't be cancelled", ErrInvalidTransition, order.Status)
+	}
+
+	return s.transition(ctx, order, &StatusHistory{
+		ToStatus: StatusCancelled,
+		ActorID:  int64(userID),
+		Note:     "Cancelled by customer",
+	})
+}
+
+func (s *service) UpdateOrderStatus(c context.Context, req *UpdateStatusReq) (*Order, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	order, err := s.repository.GetByID(ctx, int(req.OrderID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, err
+	}
+
+	return s.transition(ctx, order, &StatusHistory{
+		ToStatus: req.Status,
+		ActorID:  req.ActorID,
+		Note:     req.Note,
+	})
+}
+
+func (s *service) ListVendorOrderItems(c context.Context, req *ListVendorOrderItemReq, params pagination.Params) (*ListVendorOrderItemRes, error) {
+	page, err := pagination.NewPage(params, ListSorts...)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	items, err := s.repository.GetVendorItems(ctx, req, page)
+	if err != nil {
+		return nil, err
+	}
+
+	// Items are created along with their order, so they are keyed on the order's creation time
+	pageItems, info := pagination.Paginate(*items, page, func(item VendorOrderItem) pagination.Key {
+		return pagination.Key{ID: item.ID, CreatedAt: item.OrderedAt}
+	})
+
+	res := &ListVendorOrderItemRes{
+		Count:      len(pageItems),
+		Items:      &pageItems,
+		NextCursor: info.NextCursor,
+		HasMore:    info.HasMore,
+	}
+
+	return res, nil
+}
+
+func (s *service) FulfilOrderItem(c context.Context, req *FulfilItemReq) (*FulfilItemRes, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	fulfilment, err := s.repository.UpdateItemFulfilment(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := s.repository.GetVendorItem(ctx, int(req.ItemID), int(req.VendorID))
+	if err != nil {
+		return nil, err
+	}
+
+	res := &FulfilItemRes{
+		Item:       item,
+		Fulfilment: fulfilment,
+	}
+
+	return res, nil
+}
+
+func (s *service) GetVendorOrderFulfilment(c context.Context, orderID int, vendorID int) (*OrderFulfilment, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	fulfilment, err := s.repository.GetFulfilment(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, err
+	}
+
+	// Vendors can only see the orders containing their products
+	for _, vendor := range *fulfilment.Vendors {
+		if vendor.VendorID == int64(vendorID) {
+			return fulfilment, nil
+		}
+	}
+
+	return nil, ErrOrderNotFound
+}
@@ -0,0 +1,217 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aslam-ep/go-e-commerce/apperrors"
+)
+
+// Limits of a page, requests asking for more get the max limit
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Fields a cursor can be keyed on, the id always breaks ties so the order is total
+const (
+	SortByID        = "id"
+	SortByCreatedAt = "created_at"
+)
+
+var (
+	ErrInvalidCursor = apperrors.Validation("invalid_cursor", "cursor is invalid or does not match the sort")
+	ErrInvalidLimit  = apperrors.Validation("invalid_limit", "limit must be a positive number")
+)
+
+// Params holds the pagination query parameters of a list request, a sort prefixed with - is descending.
+type Params struct {
+	Cursor string
+	Limit  int
+	Sort   string
+}
+
+// Info holds the pagination details of a list response.
+type Info struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// Key holds the sort values of a row the next page starts after.
+type Key struct {
+	ID        int64
+	CreatedAt time.Time
+}
+
+// cursor is the opaque position encoded in the next_cursor, tied to the sort it was issued for
+type cursor struct {
+	Sort      string     `json:"s"`
+	ID        int64      `json:"i"`
+	CreatedAt *time.Time `json:"c,omitempty"`
+}
+
+// Page is a validated request for a page of rows.
+type Page struct {
+	Limit int
+	field string
+	desc  bool
+	after *cursor
+}
+
+// ParamsFromRequest reads the cursor, limit and sort query parameters of the request.
+func ParamsFromRequest(r *http.Request) (Params, error) {
+	query := r.URL.Query()
+	params := Params{
+		Cursor: query.Get("cursor"),
+		Sort:   query.Get("sort"),
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value < 1 {
+			return params, ErrInvalidLimit
+		}
+		params.Limit = value
+	}
+
+	return params, nil
+}
+
+// NewPage validates the params against the sorts allowed by the endpoint, the first one being the default.
+func NewPage(params Params, allowedSorts ...string) (*Page, error) {
+	sort := params.Sort
+	if sort == "" {
+		sort = allowedSorts[0]
+	}
+
+	allowed := false
+	for _, allowedSort := range allowedSorts {
+		if sort == allowedSort {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, apperrors.Validation("invalid_sort", "sort must be one of "+strings.Join(allowedSorts, ", "))
+	}
+
+	page := &Page{
+		Limit: params.Limit,
+		field: strings.TrimPrefix(sort, "-"),
+		desc:  strings.HasPrefix(sort, "-"),
+	}
+
+	if page.Limit <= 0 {
+		page.Limit = DefaultLimit
+	}
+	if page.Limit > MaxLimit {
+		page.Limit = MaxLimit
+	}
+
+	if params.Cursor != "" {
+		after, err := decodeCursor(params.Cursor)
+		if err != nil || after.Sort != sort || (page.field == SortByCreatedAt && after.CreatedAt == nil) {
+			return nil, ErrInvalidCursor
+		}
+		page.after = after
+	}
+
+	return page, nil
+}
+
+// Columns maps the sort fields to the qualified columns of a query joining tables.
+type Columns map[string]string
+
+// Apply appends the keyset condition, order and limit of the page to a query ending in its WHERE clause, one more
+// row than the limit is fetched to know whether another page follows.
+func (p *Page) Apply(query string, args []any) (string, []any) {
+	return p.ApplyColumns(query, args, nil)
+}
+
+// ApplyColumns is Apply for a query whose sort fields are other columns, the fields missing from the columns are
+// used as they are.
+func (p *Page) ApplyColumns(query string, args []any, columns Columns) (string, []any) {
+	direction, comparison := "ASC", ">"
+	if p.desc {
+		direction, comparison = "DESC", "<"
+	}
+
+	id, field := SortByID, p.field
+	if column, ok := columns[SortByID]; ok {
+		id = column
+	}
+	if column, ok := columns[p.field]; ok {
+		field = column
+	}
+
+	if p.after != nil {
+		if p.field == SortByID {
+			args = append(args, p.after.ID)
+			query += fmt.Sprintf(" AND %s %s $%d", id, comparison, len(args))
+		} else {
+			args = append(args, *p.after.CreatedAt, p.after.ID)
+			query += fmt.Sprintf(" AND (%s, %s) %s ($%d, $%d)", field, id, comparison, len(args)-1, len(args))
+		}
+	}
+
+	if p.field == SortByID {
+		query += fmt.Sprintf(" ORDER BY %s %s", id, direction)
+	} else {
+		query += fmt.Sprintf(" ORDER BY %s %s, %s %s", field, direction, id, direction)
+	}
+
+	args = append(args, p.Limit+1)
+	query += fmt.Sprintf(" LIMIT $%d", len(args))
+
+	return query, args
+}
+
+// Paginate trims the rows fetched by the page to its limit and returns the cursor of the next page.
+func Paginate[T any](rows []T, page *Page, keyOf func(T) Key) ([]T, Info) {
+	if len(rows) <= page.Limit {
+		return rows, Info{}
+	}
+
+	rows = rows[:page.Limit]
+	key := keyOf(rows[len(rows)-1])
+
+	next := &cursor{Sort: page.sort(), ID: key.ID}
+	if page.field == SortByCreatedAt {
+		next.CreatedAt = &key.CreatedAt
+	}
+
+	return rows, Info{NextCursor: encodeCursor(next), HasMore: true}
+}
+
+// sort returns the sort of the page as given in the params
+func (p *Page) sort() string {
+	if p.desc {
+		return "-" + p.field
+	}
+	return p.field
+}
+
+func encodeCursor(c *cursor) string {
+	// Marshalling the cursor fields cannot fail
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(value string) (*cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
@@ -0,0 +1,22 @@
+package privacy
+
+import (
+	"time"
+
+	"github.com/aslam-ep/go-e-commerce/internal/address"
+	"github.com/aslam-ep/go-e-commerce/internal/auth"
+	"github.com/aslam-ep/go-e-commerce/internal/order"
+	"github.com/aslam-ep/go-e-commerce/internal/user"
+)
+
+// ErasedName replaces the name of users whose personal data has been erased.
+const ErasedName = "Deleted User"
+
+// ExportRes represents the archive of the personal data held about a user.
+type ExportRes struct {
+	ExportedAt time.Time          `json:"exported_at"`
+	Profile    *user.User         `json:"profile"`
+	Addresses  *[]address.Address `json:"addresses"`
+	Orders     *[]order.Order     `json:"orders"`
+	Sessions   *[]auth.Session    `json:"sessions"`
+}
@@ -0,0 +1,54 @@
+package privacy
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/aslam-ep/go-e-commerce/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+// Handler struct to hold the privacy service and provide handler functions
+type Handler struct {
+	service Service
+}
+
+// NewHandler initialize and return the privacy Handler
+func NewHandler(s Service) *Handler {
+	return &Handler{
+		service: s,
+	}
+}
+
+// ExportUserData godoc
+// @Summary      Export user data
+// @Description  Download the profile, addresses, orders and sessions of the user as a JSON archive
+// @Tags         User
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        user_id  path      int  true  "User ID"
+// @Success      200      {object}  ExportRes
+// @Failure      400      {object}  utils.MessageRes
+// @Failure      401      {object}  utils.MessageRes
+// @Failure      404      {object}  utils.MessageRes
+// @Failure      500      {object}  utils.MessageRes
+// @Router       /users/{user_id}/export [get]
+func (h *Handler) ExportUserData(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "user_id"))
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.ExportUserData(r.Context(), userID)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	// Browsers save the archive rather than displaying it
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="user-%d-export.json"`, userID))
+	utils.WriteResponse(w, http.StatusOK, res)
+}
@@ -0,0 +1,49 @@
+package privacy
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// ErasureJob periodically erases the personal data of users once their deletion grace period is over.
+type ErasureJob struct {
+	service  Service
+	interval time.Duration
+}
+
+// NewErasureJob initialize and return the ErasureJob
+func NewErasureJob(s Service, interval time.Duration) *ErasureJob {
+	return &ErasureJob{
+		service:  s,
+		interval: interval,
+	}
+}
+
+// Run erases the due users right away and then on every interval until the context is cancelled.
+// A non positive interval disables the job.
+func (j *ErasureJob) Run(ctx context.Context) {
+	if j.interval <= 0 {
+		log.Println("User erasure job disabled.")
+		return
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		erased, err := j.service.EraseDueUsers(ctx)
+		if err != nil {
+			log.Println("User erasure failed:", err)
+		}
+		if erased > 0 {
+			log.Println("Erased the personal data of", erased, "deleted users.")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
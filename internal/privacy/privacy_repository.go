@@ -0,0 +1,128 @@
+package privacy
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Repository interface for the privacy repository
+type Repository interface {
+	// GetDueForErasure returns the ids of the users deleted before the given time whose data is not erased yet.
+	GetDueForErasure(ctx context.Context, deletedBefore time.Time, limit int) ([]int64, error)
+
+	// Erase anonymizes the personal data of the deleted user, keeping the orders for accounting.
+	// Returns false when the user was restored or erased in the meantime.
+	Erase(ctx context.Context, userID int64) (bool, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+// NewRepository initialize and return the Repository
+func NewRepository(db *sql.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) GetDueForErasure(ctx context.Context, deletedBefore time.Time, limit int) ([]int64, error) {
+	selectQuery := `SELECT id FROM users
+		WHERE is_deleted = true AND erased_at IS NULL AND deleted_at < $1
+		ORDER BY deleted_at
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, selectQuery, deletedBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	userIDs := []int64{}
+	for rows.Next() {
+		var userID int64
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+
+		userIDs = append(userIDs, userID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return userIDs, nil
+}
+
+func (r *repository) Erase(ctx context.Context, userID int64) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	// Rollback is a no-op once the transaction is committed
+	defer tx.Rollback()
+
+	// Lock the user so a concurrent restore can't interleave with the erasure
+	var email string
+	lockQuery := `SELECT email FROM users WHERE id = $1 AND is_deleted = true AND erased_at IS NULL FOR UPDATE`
+	err = tx.QueryRowContext(ctx, lockQuery, userID).Scan(&email)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// Failed logins are recorded by email as well, including attempts made before the user existed
+	deleteLoginAttemptsQuery := `DELETE FROM login_attempts WHERE user_id = $1 OR email = $2`
+	if _, err := tx.ExecContext(ctx, deleteLoginAttemptsQuery, userID, email); err != nil {
+		return false, err
+	}
+
+	deleteQueries := []string{
+		`DELETE FROM refresh_tokens WHERE user_id = $1`,
+		`DELETE FROM password_reset_tokens WHERE user_id = $1`,
+		`DELETE FROM email_change_tokens WHERE user_id = $1`,
+		`DELETE FROM verification_codes WHERE user_id = $1`,
+		`DELETE FROM user_mfa WHERE user_id = $1`,
+		`DELETE FROM mfa_recovery_codes WHERE user_id = $1`,
+		`DELETE FROM user_identities WHERE user_id = $1`,
+		`DELETE FROM cart_items WHERE user_id = $1`,
+	}
+	for _, query := range deleteQueries {
+		if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+			return false, err
+		}
+	}
+
+	// Deleting an address cascades to its orders, so addresses still referenced by an order are anonymized instead.
+	// The city, state and country are kept as they are needed for tax records.
+	deleteAddressQuery := `DELETE FROM addresses
+		WHERE user_id = $1 AND NOT EXISTS (SELECT 1 FROM orderes WHERE orderes.address_id = addresses.id)`
+	if _, err := tx.ExecContext(ctx, deleteAddressQuery, userID); err != nil {
+		return false, err
+	}
+
+	anonymizeAddressQuery := `UPDATE addresses
+		SET address_line1 = '', address_line2 = '', postal_code = '', is_default = false, is_deleted = true, updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1`
+	if _, err := tx.ExecContext(ctx, anonymizeAddressQuery, userID); err != nil {
+		return false, err
+	}
+
+	// The placeholder email frees the original email and phone for a new registration
+	anonymizeUserQuery := `UPDATE users
+		SET name = $2, email = 'erased-' || id || '@erased.invalid', phone = NULL, password = '',
+			email_verified_at = NULL, phone_verified_at = NULL, failed_login_attempts = 0, locked_until = NULL,
+			erased_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, anonymizeUserQuery, userID, ErasedName); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,193 @@
+package privacy
+
+import (
+	"context"
+	"time"
+
+	"github.com/aslam-ep/go-e-commerce/config"
+	"github.com/aslam-ep/go-e-commerce/internal/address"
+	"github.com/aslam-ep/go-e-commerce/internal/auth"
+	"github.com/aslam-ep/go-e-commerce/internal/order"
+	"github.com/aslam-ep/go-e-commerce/internal/pagination"
+	"github.com/aslam-ep/go-e-commerce/internal/user"
+)
+
+const (
+	// erasureBatchSize limits the users erased per query for the due users
+	erasureBatchSize = 100
+)
+
+// Service interface for the privacy service
+type Service interface {
+	// ExportUserData returns the profile, addresses, orders and sessions of the user as a single archive.
+	ExportUserData(c context.Context, userID int) (*ExportRes, error)
+
+	// EraseDueUsers erases the personal data of the users deleted longer than the grace period ago
+	// and returns the number of users erased.
+	EraseDueUsers(c context.Context) (int, error)
+}
+
+type service struct {
+	privacyRepo Repository
+	userRepo    user.Repository
+	addressRepo address.Repository
+	orderRepo   order.Repository
+	authService auth.Service
+	gracePeriod time.Duration
+	timeout     time.Duration
+}
+
+// NewService initialize and return the Service
+func NewService(pr Repository, ur user.Repository, ar address.Repository, or order.Repository, as auth.Service) Service {
+	return &service{
+		privacyRepo: pr,
+		userRepo:    ur,
+		addressRepo: ar,
+		orderRepo:   or,
+		authService: as,
+		gracePeriod: time.Duration(config.AppConfig.ErasureGraceDays) * 24 * time.Hour,
+		timeout:     time.Duration(config.AppConfig.DBTimeout) * time.Second,
+	}
+}
+
+func (s *service) ExportUserData(c context.Context, userID int) (*ExportRes, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	profile, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	profile.Password = ""
+
+	addresses, err := s.getAllAddresses(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := s.getAllOrders(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.authService.ListSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ExportRes{
+		ExportedAt: time.Now(),
+		Profile:    profile,
+		Addresses:  addresses,
+		Orders:     orders,
+		Sessions:   sessions.Sessions,
+	}
+
+	return res, nil
+}
+
+// getAllAddresses collects every address of the user page by page
+func (s *service) getAllAddresses(ctx context.Context, userID int) (*[]address.Address, error) {
+	addresses := []address.Address{}
+	params := pagination.Params{Limit: pagination.MaxLimit}
+
+	for {
+		page, err := pagination.NewPage(params, pagination.SortByID)
+		if err != nil {
+			return nil, err
+		}
+
+		pageAddresses, err := s.addressRepo.GetAll(ctx, userID, page)
+		if err != nil {
+			return nil, err
+		}
+
+		rows, info := pagination.Paginate(*pageAddresses, page, func(a address.Address) pagination.Key {
+			return pagination.Key{ID: a.ID}
+		})
+		addresses = append(addresses, rows...)
+
+		if !info.HasMore {
+			return &addresses, nil
+		}
+		params.Cursor = info.NextCursor
+	}
+}
+
+// getAllOrders collects every order of the user page by page along with its items
+func (s *service) getAllOrders(ctx context.Context, userID int) (*[]order.Order, error) {
+	orders := []order.Order{}
+	params := pagination.Params{Limit: pagination.MaxLimit}
+
+	for {
+		page, err := pagination.NewPage(params, pagination.SortByID)
+		if err != nil {
+			return nil, err
+		}
+
+		pageOrders, err := s.orderRepo.GetAll(ctx, &order.ListOrderReq{UserID: int64(userID)}, page)
+		if err != nil {
+			return nil, err
+		}
+
+		rows, info := pagination.Paginate(*pageOrders, page, func(o order.Order) pagination.Key {
+			return pagination.Key{ID: o.ID}
+		})
+
+		for _, o := range rows {
+			o.Items, err = s.orderRepo.GetItems(ctx, int(o.ID))
+			if err != nil {
+				return nil, err
+			}
+
+			orders = append(orders, o)
+		}
+
+		if !info.HasMore {
+			return &orders, nil
+		}
+		params.Cursor = info.NextCursor
+	}
+}
+
+func (s *service) EraseDueUsers(c context.Context) (int, error) {
+	erased := 0
+	deletedBefore := time.Now().Add(-s.gracePeriod)
+
+	for {
+		userIDs, err := s.getDueForErasure(c, deletedBefore)
+		if err != nil {
+			return erased, err
+		}
+
+		for _, userID := range userIDs {
+			ok, err := s.erase(c, userID)
+			if err != nil {
+				return erased, err
+			}
+			if ok {
+				erased++
+			}
+		}
+
+		if len(userIDs) < erasureBatchSize {
+			return erased, nil
+		}
+	}
+}
+
+// getDueForErasure returns the next batch of users to erase
+func (s *service) getDueForErasure(c context.Context, deletedBefore time.Time) ([]int64, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	return s.privacyRepo.GetDueForErasure(ctx, deletedBefore, erasureBatchSize)
+}
+
+// erase erases a single user within its own timeout
+func (s *service) erase(c context.Context, userID int64) (bool, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	return s.privacyRepo.Erase(ctx, userID)
+}
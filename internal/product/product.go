@@ -0,0 +1,31 @@
+package product
+
+import "time"
+
+// Product represents a product listed by a vendor in the catalog.
+type Product struct {
+	ID          int64     `json:"id"`
+	VendorID    int64     `json:"vendor_id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Price       float64   `json:"price"`
+	StockCount  int       `json:"stock_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CreateUpdateProductReq represents the request payload for creating/updating product
+type CreateUpdateProductReq struct {
+	ID          int64   `json:"id,omitempty"`
+	VendorID    int64   `json:"vendor_id,omitempty"`
+	Name        string  `json:"name" validate:"required,min=3,max=100"`
+	Description string  `json:"description" validate:"max=255"`
+	Price       float64 `json:"price" validate:"gt=0"`
+	StockCount  int     `json:"stock_count" validate:"gte=0"`
+}
+
+// ListProductRes struct for returning set of products
+type ListProductRes struct {
+	Count    int        `json:"count"`
+	Products *[]Product `json:"products"`
+}
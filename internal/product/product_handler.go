@@ -0,0 +1,213 @@
+package product
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/aslam-ep/go-e-commerce/router/middleware"
+	"github.com/aslam-ep/go-e-commerce/utils"
+	"github.com/go-chi/chi/v5"
+)
+
+// Handler handles HTTP requests related to products.
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new instance of the Handler with the provided product service.
+func NewHandler(service Service) *Handler {
+	return &Handler{
+		service: service,
+	}
+}
+
+func (h *Handler) getIDsFromRequest(r *http.Request) (int, int, error) {
+	vendorID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		return -1, -1, err
+	}
+
+	productIDStr := chi.URLParam(r, "product_id")
+	productID, err := strconv.Atoi(productIDStr)
+	if err != nil {
+		return -1, -1, err
+	}
+
+	return productID, vendorID, nil
+}
+
+// CreateProduct godoc
+// @Summary      Adding a new product
+// @Description  Adding a new product for the authenticated vendor
+// @Tags         Product
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      CreateUpdateProductReq  true  "Product request for create and update"
+// @Success      200   {object}  Product
+// @Failure      400   {object}  utils.MessageRes
+// @Failure      401   {object}  utils.MessageRes
+// @Failure      403   {object}  utils.MessageRes
+// @Failure      500   {object}  utils.MessageRes
+// @Router       /products/create [post]
+func (h *Handler) CreateProduct(w http.ResponseWriter, r *http.Request) {
+	var productReq CreateUpdateProductReq
+	if err := utils.ReadFromRequest(r, &productReq); err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	vendorID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+	productReq.VendorID = int64(vendorID)
+
+	if err := utils.Validate.Struct(productReq); err != nil {
+		utils.WriteValidationError(w, r, err)
+		return
+	}
+
+	res, err := h.service.CreateProduct(r.Context(), &productReq)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// GetAllProducts godoc
+// @Summary      Get all products
+// @Description  Get all products in the catalog, optionally filtered by vendor
+// @Tags         Product
+// @Accept       json
+// @Produce      json
+// @Param        vendor_id  query     int  false  "Vendor ID"
+// @Success      200        {object}  ListProductRes
+// @Failure      400        {object}  utils.MessageRes
+// @Failure      500        {object}  utils.MessageRes
+// @Router       /products/ [get]
+func (h *Handler) GetAllProducts(w http.ResponseWriter, r *http.Request) {
+	vendorID := 0
+	if vendorIDStr := r.URL.Query().Get("vendor_id"); vendorIDStr != "" {
+		id, err := strconv.Atoi(vendorIDStr)
+		if err != nil {
+			utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		vendorID = id
+	}
+
+	res, err := h.service.GetAllProducts(r.Context(), vendorID)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// GetProductByID godoc
+// @Summary      Get product by ID
+// @Description  Get a specific product from the catalog by ID
+// @Tags         Product
+// @Accept       json
+// @Produce      json
+// @Param        product_id  path      int  true  "Product ID"
+// @Success      200         {object}  Product
+// @Failure      400         {object}  utils.MessageRes
+// @Failure      404         {object}  utils.MessageRes
+// @Router       /products/{product_id} [get]
+func (h *Handler) GetProductByID(w http.ResponseWriter, r *http.Request) {
+	productIDStr := chi.URLParam(r, "product_id")
+	productID, err := strconv.Atoi(productIDStr)
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.GetProductByID(r.Context(), productID)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// UpdateProduct godoc
+// @Summary      Update product by ID
+// @Description  Update a product owned by the authenticated vendor
+// @Tags         Product
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        product_id  path      int                     true  "Product ID"
+// @Param        body        body      CreateUpdateProductReq  true  "Product request for create and update"
+// @Success      200         {object}  Product
+// @Failure      400         {object}  utils.MessageRes
+// @Failure      401         {object}  utils.MessageRes
+// @Failure      403         {object}  utils.MessageRes
+// @Failure      404         {object}  utils.MessageRes
+// @Router       /products/{product_id}/update [put]
+func (h *Handler) UpdateProduct(w http.ResponseWriter, r *http.Request) {
+	var productReq CreateUpdateProductReq
+	if err := utils.ReadFromRequest(r, &productReq); err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	productID, vendorID, err := h.getIDsFromRequest(r)
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	productReq.ID = int64(productID)
+	productReq.VendorID = int64(vendorID)
+
+	if err := utils.Validate.Struct(productReq); err != nil {
+		utils.WriteValidationError(w, r, err)
+		return
+	}
+
+	res, err := h.service.UpdateProduct(r.Context(), &productReq)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// DeleteProduct godoc
+// @Summary      Delete product
+// @Description  Soft delete a product owned by the authenticated vendor
+// @Tags         Product
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        product_id  path      int  true  "Product ID"
+// @Success      200         {object}  utils.MessageRes
+// @Failure      400         {object}  utils.MessageRes
+// @Failure      401         {object}  utils.MessageRes
+// @Failure      403         {object}  utils.MessageRes
+// @Failure      404         {object}  utils.MessageRes
+// @Router       /products/{product_id}/delete [delete]
+func (h *Handler) DeleteProduct(w http.ResponseWriter, r *http.Request) {
+	productID, vendorID, err := h.getIDsFromRequest(r)
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.DeleteProduct(r.Context(), productID, vendorID)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
@@ -0,0 +1,146 @@
+package product
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/aslam-ep/go-e-commerce/apperrors"
+)
+
+// Repository interface for product repository
+type Repository interface {
+	// Create Create a new product for the given vendor
+	Create(ctx context.Context, product *Product) (*Product, error)
+
+	// GetAll Get all the products, filtered by vendor ID when it is greater than zero
+	GetAll(ctx context.Context, vendorID int) (*[]Product, error)
+
+	// GetByID Get product by the given ID
+	GetByID(ctx context.Context, id int) (*Product, error)
+
+	// Update Update the product
+	Update(ctx context.Context, product *Product) (*Product, error)
+
+	// Delete Soft delete the product owned by the given vendor
+	Delete(ctx context.Context, id int, vendorID int) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+// NewRepository initialize and returns product repository
+func NewRepository(db *sql.DB) Repository {
+	return &repository{
+		db: db,
+	}
+}
+
+func (r *repository) Create(ctx context.Context, product *Product) (*Product, error) {
+	insertQuery := `INSERT INTO products(vendor_id, name, description, price, stock_count) VALUES($1, $2, $3, $4, $5) RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, insertQuery,
+		product.VendorID,
+		product.Name,
+		product.Description,
+		product.Price,
+		product.StockCount,
+	).Scan(
+		&product.ID,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, apperrors.FromDB(err, "product")
+	}
+
+	return product, nil
+}
+
+func (r *repository) GetAll(ctx context.Context, vendorID int) (*[]Product, error) {
+	selectQuery := `SELECT id, vendor_id, name, COALESCE(description, ''), price, stock_count, created_at, updated_at FROM products WHERE is_deleted = false AND ($1 = 0 OR vendor_id = $1) ORDER BY id;`
+
+	rows, err := r.db.QueryContext(ctx, selectQuery, vendorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	products := []Product{}
+	for rows.Next() {
+		var product Product
+		if err := rows.Scan(
+			&product.ID,
+			&product.VendorID,
+			&product.Name,
+			&product.Description,
+			&product.Price,
+			&product.StockCount,
+			&product.CreatedAt,
+			&product.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		products = append(products, product)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &products, nil
+}
+
+func (r *repository) GetByID(ctx context.Context, id int) (*Product, error) {
+	var product Product
+	selectByIDQuery := `SELECT id, vendor_id, name, COALESCE(description, ''), price, stock_count, created_at, updated_at FROM products WHERE id = $1 AND is_deleted = false;`
+
+	err := r.db.QueryRowContext(ctx, selectByIDQuery, id).Scan(
+		&product.ID,
+		&product.VendorID,
+		&product.Name,
+		&product.Description,
+		&product.Price,
+		&product.StockCount,
+		&product.CreatedAt,
+		&product.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, apperrors.FromDB(err, "product")
+	}
+
+	return &product, nil
+}
+
+func (r *repository) Update(ctx context.Context, product *Product) (*Product, error) {
+	product.UpdatedAt = time.Now()
+	updateQuery := `UPDATE products SET name = $1, description = $2, price = $3, stock_count = $4, updated_at = $5 WHERE id = $6 AND vendor_id = $7 AND is_deleted = false RETURNING created_at;`
+
+	err := r.db.QueryRowContext(ctx, updateQuery,
+		product.Name,
+		product.Description,
+		product.Price,
+		product.StockCount,
+		product.UpdatedAt,
+		product.ID,
+		product.VendorID,
+	).Scan(&product.CreatedAt)
+
+	if err != nil {
+		return nil, apperrors.FromDB(err, "product")
+	}
+
+	return product, nil
+}
+
+func (r *repository) Delete(ctx context.Context, id int, vendorID int) error {
+	deleteQuery := `UPDATE products SET is_deleted = true, updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND vendor_id = $2;`
+
+	_, err := r.db.ExecContext(ctx, deleteQuery, id, vendorID)
+
+	return err
+}
@@ -0,0 +1,156 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aslam-ep/go-e-commerce/apperrors"
+	"github.com/aslam-ep/go-e-commerce/config"
+	"github.com/aslam-ep/go-e-commerce/utils"
+)
+
+// ErrNotProductOwner is returned when a vendor tries to manage another vendor's product.
+var ErrNotProductOwner = apperrors.Forbidden("not_product_owner", "product doesn't belong to the vendor")
+
+// Service interface defines the methods required for product services.
+type Service interface {
+	// CreateProduct Creates a new product for the vendor and returns the created product details.
+	CreateProduct(c context.Context, req *CreateUpdateProductReq) (*Product, error)
+
+	// GetAllProducts Get all products, filtered by vendor ID when it is greater than zero
+	GetAllProducts(c context.Context, vendorID int) (*ListProductRes, error)
+
+	// GetProductByID Get a product by the product ID and return it
+	GetProductByID(c context.Context, id int) (*Product, error)
+
+	// UpdateProduct Update the vendor's product based on request and returns the updated product
+	UpdateProduct(c context.Context, req *CreateUpdateProductReq) (*Product, error)
+
+	// DeleteProduct Soft delete the vendor's product based on given ID
+	DeleteProduct(c context.Context, id int, vendorID int) (*utils.MessageRes, error)
+}
+
+type service struct {
+	repository Repository
+	timeout    time.Duration
+}
+
+// NewService creates a new instance of the product service.
+func NewService(productRepo Repository) Service {
+	return &service{
+		repository: productRepo,
+		timeout:    time.Duration(config.AppConfig.DBTimeout) * time.Second,
+	}
+}
+
+// getOwnedProduct returns the product when it belongs to the given vendor
+func (s *service) getOwnedProduct(ctx context.Context, id int, vendorID int) (*Product, error) {
+	product, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if product.VendorID != int64(vendorID) {
+		return nil, ErrNotProductOwner
+	}
+
+	return product, nil
+}
+
+func (s *service) CreateProduct(c context.Context, req *CreateUpdateProductReq) (*Product, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	p := &Product{
+		VendorID:    req.VendorID,
+		Name:        req.Name,
+		Description: req.Description,
+		Price:       req.Price,
+		StockCount:  req.StockCount,
+	}
+
+	product, err := s.repository.Create(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+func (s *service) GetAllProducts(c context.Context, vendorID int) (*ListProductRes, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	products, err := s.repository.GetAll(ctx, vendorID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ListProductRes{
+		Count:    len(*products),
+		Products: products,
+	}
+
+	return res, nil
+}
+
+func (s *service) GetProductByID(c context.Context, id int) (*Product, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	product, err := s.repository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
+
+func (s *service) UpdateProduct(c context.Context, req *CreateUpdateProductReq) (*Product, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	_, err := s.getOwnedProduct(ctx, int(req.ID), int(req.VendorID))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Product{
+		ID:          req.ID,
+		VendorID:    req.VendorID,
+		Name:        req.Name,
+		Description: req.Description,
+		Price:       req.Price,
+		StockCount:  req.StockCount,
+	}
+
+	updatedProduct, err := s.repository.Update(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return updatedProduct, nil
+}
+
+func (s *service) DeleteProduct(c context.Context, id int, vendorID int) (*utils.MessageRes, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	_, err := s.getOwnedProduct(ctx, id, vendorID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repository.Delete(ctx, id, vendorID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &utils.MessageRes{
+		Success: true,
+		Message: fmt.Sprintf("Product(%d) deleted.", id),
+	}
+
+	return res, nil
+}
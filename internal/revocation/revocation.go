@@ -0,0 +1,34 @@
+package revocation
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Store keeps track of revoked tokens, either a single access token by its jti
+// or every token of a user issued before a point in time.
+type Store interface {
+	// Revoke revokes the token with the given jti until it expires.
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) error
+
+	// RevokeUser revokes every token of the user issued before the second of the given time, tokens carry their
+	// issue time in whole seconds so one issued later in that same second stays valid.
+	RevokeUser(ctx context.Context, userID int64, issuedBefore time.Time) error
+
+	// IsRevoked reports whether the token of the user issued at the given time is revoked, jti can be empty for tokens without one.
+	IsRevoked(ctx context.Context, jti string, userID int64, issuedAt time.Time) (bool, error)
+}
+
+// NewStore initialize and returns the revocation store of the given kind, memory or postgres.
+func NewStore(kind string, db *sql.DB) (Store, error) {
+	switch kind {
+	case "memory":
+		return NewMemoryStore(), nil
+	case "postgres":
+		return NewPostgresStore(db), nil
+	default:
+		return nil, fmt.Errorf("unknown token revocation store %q, expected memory or postgres", kind)
+	}
+}
@@ -0,0 +1,66 @@
+package revocation
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryStore keeps the revocations in process, suitable for a single instance or local development.
+type memoryStore struct {
+	mu    sync.RWMutex
+	jtis  map[string]time.Time
+	users map[int64]time.Time
+}
+
+// NewMemoryStore initialize and returns an in-memory revocation store
+func NewMemoryStore() Store {
+	return &memoryStore{
+		jtis:  make(map[string]time.Time),
+		users: make(map[int64]time.Time),
+	}
+}
+
+func (s *memoryStore) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Drop the revocations of tokens which have expired on their own
+	now := time.Now()
+	for id, exp := range s.jtis {
+		if exp.Before(now) {
+			delete(s.jtis, id)
+		}
+	}
+
+	s.jtis[jti] = expiresAt
+
+	return nil
+}
+
+func (s *memoryStore) RevokeUser(ctx context.Context, userID int64, issuedBefore time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	issuedBefore = issuedBefore.Truncate(time.Second)
+	if current, ok := s.users[userID]; !ok || issuedBefore.After(current) {
+		s.users[userID] = issuedBefore
+	}
+
+	return nil
+}
+
+func (s *memoryStore) IsRevoked(ctx context.Context, jti string, userID int64, issuedAt time.Time) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.jtis[jti]; ok && jti != "" {
+		return true, nil
+	}
+
+	if revokedBefore, ok := s.users[userID]; ok && issuedAt.Before(revokedBefore) {
+		return true, nil
+	}
+
+	return false, nil
+}
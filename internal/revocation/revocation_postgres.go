@@ -0,0 +1,55 @@
+package revocation
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// postgresStore keeps the revocations in the database so they are shared across instances.
+type postgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore initialize and returns a postgres backed revocation store
+func NewPostgresStore(db *sql.DB) Store {
+	return &postgresStore{
+		db: db,
+	}
+}
+
+func (s *postgresStore) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	// Drop the revocations of tokens which have expired on their own
+	cleanupQuery := `DELETE FROM revoked_tokens WHERE expires_at < CURRENT_TIMESTAMP`
+	if _, err := s.db.ExecContext(ctx, cleanupQuery); err != nil {
+		return err
+	}
+
+	insertQuery := `INSERT INTO revoked_tokens(jti, expires_at) VALUES ($1, $2) ON CONFLICT (jti) DO NOTHING`
+
+	_, err := s.db.ExecContext(ctx, insertQuery, jti, expiresAt)
+
+	return err
+}
+
+func (s *postgresStore) RevokeUser(ctx context.Context, userID int64, issuedBefore time.Time) error {
+	upsertQuery := `INSERT INTO user_token_revocations(user_id, revoked_before) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET revoked_before = GREATEST(user_token_revocations.revoked_before, EXCLUDED.revoked_before)`
+
+	_, err := s.db.ExecContext(ctx, upsertQuery, userID, issuedBefore.Truncate(time.Second))
+
+	return err
+}
+
+func (s *postgresStore) IsRevoked(ctx context.Context, jti string, userID int64, issuedAt time.Time) (bool, error) {
+	var revoked bool
+	selectQuery := `SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE jti = $1)
+		OR EXISTS(SELECT 1 FROM user_token_revocations WHERE user_id = $2 AND revoked_before > $3)`
+
+	err := s.db.QueryRowContext(ctx, selectQuery, jti, userID, issuedAt).Scan(&revoked)
+	if err != nil {
+		return false, err
+	}
+
+	return revoked, nil
+}
@@ -2,6 +2,31 @@ package user
 
 import (
 	"time"
+
+	"github.com/aslam-ep/go-e-commerce/apperrors"
+)
+
+// ErrEmailTaken is returned when the email already belongs to another user.
+var ErrEmailTaken = apperrors.Conflict("email_taken", "email is already in use")
+
+// ErrPhoneTaken is returned when the phone already belongs to another user.
+var ErrPhoneTaken = apperrors.Conflict("phone_taken", "phone is already in use")
+
+// Errors returned by the admin user management.
+var (
+	ErrUserNotDeleted      = apperrors.Conflict("user_not_deleted", "user is not deleted")
+	ErrCannotChangeOwnRole = apperrors.Forbidden("own_role_change", "admins can't change their own role")
+	ErrUserErased          = apperrors.Conflict("user_erased", "user data has been erased")
+)
+
+// ErrPasswordMismatch is returned when the current password given for a password change doesn't match.
+var ErrPasswordMismatch = apperrors.Validation("password_mismatch", "current password doesn't match")
+
+// User roles, admins can't be self assigned and are promoted directly in the database.
+const (
+	RoleUser   = "user"
+	RoleVendor = "vendor"
+	RoleAdmin  = "admin"
 )
 
 // User represents the user entity in the system.
@@ -14,6 +39,26 @@ type User struct {
 	Password  string    `json:"password,omitempty"`
 	CreatedAt time.Time `json:"created_at,omitempty"`
 	UpdatedAt time.Time `json:"updated_at,omitempty"`
+
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	PhoneVerifiedAt *time.Time `json:"phone_verified_at"`
+
+	FailedLoginAttempts int        `json:"-"`
+	LockedUntil         *time.Time `json:"locked_until,omitempty"`
+
+	IsDeleted bool       `json:"is_deleted,omitempty"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	ErasedAt  *time.Time `json:"erased_at,omitempty"`
+}
+
+// IsLocked reports whether the user is locked out of login at the given time.
+func (u *User) IsLocked(now time.Time) bool {
+	return u.LockedUntil != nil && u.LockedUntil.After(now)
+}
+
+// IsVerified reports whether the user has verified their email, the phone is verified optionally.
+func (u *User) IsVerified() bool {
+	return u.EmailVerifiedAt != nil
 }
 
 // UpdateUserReq represents the request payload for updating user details.
@@ -21,7 +66,7 @@ type UpdateUserReq struct {
 	ID    int64  `json:"id" validate:"required"`
 	Name  string `json:"name" validate:"required,min=3,max=100"`
 	Phone string `json:"phone" validate:"required,e164"`
-	Role  string `json:"role" validate:"required,oneof=user vendor"`
+	Role  string `json:"role" validate:"omitempty,oneof=user vendor"`
 }
 
 // ResetPasswordReq represents the request payload for resetting a user's password.
@@ -30,3 +75,25 @@ type ResetPasswordReq struct {
 	CurrentPassword string `json:"current_password" validate:"required,min=6"`
 	NewPassword     string `json:"new_password" validate:"required,min=6"`
 }
+
+// ListUserReq represents the search and filters for listing users.
+type ListUserReq struct {
+	Query   string `json:"q"`
+	Role    string `json:"role" validate:"omitempty,oneof=user vendor admin"`
+	Deleted string `json:"deleted" validate:"omitempty,oneof=exclude include only"`
+}
+
+// ListUserRes represents a page of users, has_more tells whether a next page exists.
+type ListUserRes struct {
+	Count      int     `json:"count"`
+	Users      *[]User `json:"users"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+	HasMore    bool    `json:"has_more"`
+}
+
+// ChangeRoleReq represents the request payload for changing a user's role.
+type ChangeRoleReq struct {
+	ID      int64  `json:"-"`
+	AdminID int64  `json:"-"`
+	Role    string `json:"role" validate:"required,oneof=user vendor admin"`
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/aslam-ep/go-e-commerce/internal/pagination"
+	"github.com/aslam-ep/go-e-commerce/router/middleware"
 	"github.com/aslam-ep/go-e-commerce/utils"
 	"github.com/go-chi/chi/v5"
 )
@@ -34,13 +36,13 @@ func (h *Handler) GetUser(w http.ResponseWriter, r *http.Request) {
 	userIDstr := chi.URLParam(r, "user_id")
 	userID, err := strconv.Atoi(userIDstr)
 	if err != nil {
-		utils.WriterErrorResponse(w, http.StatusBadRequest, err.Error())
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	res, err := h.service.GetUserByID(r.Context(), userID)
 	if err != nil {
-		utils.WriterErrorResponse(w, http.StatusInternalServerError, err.Error())
+		utils.WriteError(w, r, err)
 		return
 	}
 
@@ -57,30 +59,31 @@ func (h *Handler) GetUser(w http.ResponseWriter, r *http.Request) {
 // @Param        body  body  UpdateUserReq  true  "User Update request"
 // @Success      200  {object}  User
 // @Failure      400  {object}  utils.MessageRes
+// @Failure      409  {object}  utils.MessageRes
 // @Router       /users/{user_id}/update [put]
 func (h *Handler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 	userIDstr := chi.URLParam(r, "user_id")
 	userID, err := strconv.Atoi(userIDstr)
 	if err != nil {
-		utils.WriterErrorResponse(w, http.StatusBadRequest, err.Error())
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	var updateUserReq UpdateUserReq
 	if err := utils.ReadFromRequest(r, &updateUserReq); err != nil {
-		utils.WriterErrorResponse(w, http.StatusBadRequest, err.Error())
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 	updateUserReq.ID = int64(userID)
 
 	if err := utils.Validate.Struct(updateUserReq); err != nil {
-		utils.WriterErrorResponse(w, http.StatusBadRequest, err.Error())
+		utils.WriteValidationError(w, r, err)
 		return
 	}
 
 	res, err := h.service.UpdateUser(r.Context(), &updateUserReq)
 	if err != nil {
-		utils.WriterErrorResponse(w, http.StatusInternalServerError, err.Error())
+		utils.WriteError(w, r, err)
 		return
 	}
 
@@ -102,13 +105,13 @@ func (h *Handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 	userIDstr := chi.URLParam(r, "user_id")
 	userID, err := strconv.Atoi(userIDstr)
 	if err != nil {
-		utils.WriterErrorResponse(w, http.StatusBadRequest, err.Error())
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	var resetPasswordReq ResetPasswordReq
 	if err := utils.ReadFromRequest(r, &resetPasswordReq); err != nil {
-		utils.WriterErrorResponse(w, http.StatusBadRequest, err.Error())
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -116,7 +119,7 @@ func (h *Handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
 
 	res, err := h.service.ChangeUserPassword(r.Context(), &resetPasswordReq)
 	if err != nil {
-		utils.WriterErrorResponse(w, http.StatusInternalServerError, err.Error())
+		utils.WriteError(w, r, err)
 		return
 	}
 
@@ -137,13 +140,172 @@ func (h *Handler) DeleteUser(w http.ResponseWriter, r *http.Request) {
 	userIDstr := chi.URLParam(r, "user_id")
 	userID, err := strconv.Atoi(userIDstr)
 	if err != nil {
-		utils.WriterErrorResponse(w, http.StatusBadRequest, err.Error())
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	res, err := h.service.DeleteUser(r.Context(), userID)
 	if err != nil {
-		utils.WriterErrorResponse(w, http.StatusInternalServerError, err.Error())
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// ListUsers     godoc
+// @Summary      List users
+// @Description  Search users by name, email or phone with role and deleted filters page by page, pass the next_cursor of a page to get the next one, admin only
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        q        query     string  false  "Name, email or phone contains"
+// @Param        role     query     string  false  "User role" Enums(user, vendor, admin)
+// @Param        deleted  query     string  false  "Soft deleted users, excluded by default" Enums(exclude, include, only)
+// @Param        cursor   query     string  false  "Cursor of the page to get"
+// @Param        limit    query     int     false  "Users per page, max 100"
+// @Param        sort     query     string  false  "Sort, one of -created_at, created_at, -id, id, newest first by default"
+// @Success      200      {object}  ListUserRes
+// @Failure      400      {object}  utils.MessageRes
+// @Failure      401      {object}  utils.MessageRes
+// @Failure      403      {object}  utils.MessageRes
+// @Failure      500      {object}  utils.MessageRes
+// @Router       /admin/users [get]
+func (h *Handler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	listReq := ListUserReq{
+		Query:   query.Get("q"),
+		Role:    query.Get("role"),
+		Deleted: query.Get("deleted"),
+	}
+
+	if err := utils.Validate.Struct(listReq); err != nil {
+		utils.WriteValidationError(w, r, err)
+		return
+	}
+
+	params, err := pagination.ParamsFromRequest(r)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	res, err := h.service.ListUsers(r.Context(), &listReq, params)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// GetUserForAdmin godoc
+// @Summary      Get user
+// @Description  Get the user details including soft deleted and locked users, admin only
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        user_id  path      int  true  "User ID"
+// @Success      200      {object}  User
+// @Failure      400      {object}  utils.MessageRes
+// @Failure      401      {object}  utils.MessageRes
+// @Failure      403      {object}  utils.MessageRes
+// @Failure      404      {object}  utils.MessageRes
+// @Router       /admin/users/{user_id} [get]
+func (h *Handler) GetUserForAdmin(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "user_id"))
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.GetUserForAdmin(r.Context(), userID)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// RestoreUser   godoc
+// @Summary      Restore user
+// @Description  Restore a soft deleted user, admin only
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        user_id  path      int  true  "User ID"
+// @Success      200      {object}  utils.MessageRes
+// @Failure      400      {object}  utils.MessageRes
+// @Failure      401      {object}  utils.MessageRes
+// @Failure      403      {object}  utils.MessageRes
+// @Failure      404      {object}  utils.MessageRes
+// @Failure      409      {object}  utils.MessageRes
+// @Router       /admin/users/{user_id}/restore [put]
+func (h *Handler) RestoreUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "user_id"))
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := h.service.RestoreUser(r.Context(), userID)
+	if err != nil {
+		utils.WriteError(w, r, err)
+		return
+	}
+
+	utils.WriteResponse(w, http.StatusOK, res)
+}
+
+// ChangeUserRole godoc
+// @Summary      Change user role
+// @Description  Change the role of a user and revoke their access tokens, admins can't change their own role
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        user_id  path      int            true  "User ID"
+// @Param        body     body      ChangeRoleReq  true  "New role"
+// @Success      200      {object}  utils.MessageRes
+// @Failure      400      {object}  utils.MessageRes
+// @Failure      401      {object}  utils.MessageRes
+// @Failure      403      {object}  utils.MessageRes
+// @Failure      404      {object}  utils.MessageRes
+// @Router       /admin/users/{user_id}/role [put]
+func (h *Handler) ChangeUserRole(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "user_id"))
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	adminID, err := middleware.GetUserID(r.Context())
+	if err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var changeRoleReq ChangeRoleReq
+	if err := utils.ReadFromRequest(r, &changeRoleReq); err != nil {
+		utils.WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := utils.Validate.Struct(changeRoleReq); err != nil {
+		utils.WriteValidationError(w, r, err)
+		return
+	}
+
+	changeRoleReq.ID = int64(userID)
+	changeRoleReq.AdminID = int64(adminID)
+
+	res, err := h.service.ChangeUserRole(r.Context(), &changeRoleReq)
+	if err != nil {
+		utils.WriteError(w, r, err)
 		return
 	}
 
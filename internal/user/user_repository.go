@@ -3,7 +3,14 @@ package user
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"strings"
 	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/aslam-ep/go-e-commerce/apperrors"
+	"github.com/aslam-ep/go-e-commerce/internal/pagination"
 )
 
 // Repository interface for the user repository
@@ -17,14 +24,49 @@ type Repository interface {
 	// GetByID find and returns the user, by user id
 	GetByID(ctx context.Context, id int) (*User, error)
 
-	// Update update user by user id and returns the updated user.
+	// Update update user by user id and returns the updated user, ErrPhoneTaken is returned if another user has the phone.
 	Update(ctx context.Context, user *User) (*User, error)
 
-	// ChangePassword update the user password by the user id
+	// ChangePassword update the user password by the user id and revokes the user's refresh tokens
 	ChangePassword(ctx context.Context, userID int, password string) error
 
-	// Delete delete the given user based on user id
+	// Delete delete the given user based on user id and revokes the user's refresh tokens
 	Delete(ctx context.Context, userID int) error
+
+	// MarkEmailVerified marks the email of the user as verified if it still matches the given email.
+	MarkEmailVerified(ctx context.Context, userID int, email string) error
+
+	// MarkPhoneVerified marks the phone of the user as verified if it still matches the given phone.
+	MarkPhoneVerified(ctx context.Context, userID int, phone string) error
+
+	// IncrementFailedLogins counts a failed login of the user and returns the consecutive failures.
+	IncrementFailedLogins(ctx context.Context, userID int) (int, error)
+
+	// LockUntil locks the user out of login until the given time.
+	LockUntil(ctx context.Context, userID int, until time.Time) error
+
+	// ResetFailedLogins clears the failed logins and the lock of the user.
+	ResetFailedLogins(ctx context.Context, userID int) error
+
+	// GetAll returns the page of users matching the search and filters.
+	GetAll(ctx context.Context, req *ListUserReq, page *pagination.Page) (*[]User, error)
+
+	// GetByIDWithDeleted find and returns the user by user id, including soft deleted users.
+	GetByIDWithDeleted(ctx context.Context, id int) (*User, error)
+
+	// Restore clears the soft delete of the user, unless its data has been erased.
+	Restore(ctx context.Context, userID int) error
+
+	// UpdateEmail replaces the email of the user with the confirmed email, ErrEmailTaken is returned if another user has it.
+	UpdateEmail(ctx context.Context, userID int, email string) error
+
+	// UpdateRole changes the role of the user.
+	UpdateRole(ctx context.Context, userID int, role string) error
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
 }
 
 type repository struct {
@@ -42,7 +84,8 @@ func (r *repository) Create(ctx context.Context, user *User) (*User, error) {
 		createdAt time.Time
 		updatedAt time.Time
 	)
-	insertQuery := `INSERT INTO users(name, email, phone, role, password) VALUES($1, $2, $3, $4, $5) RETURNING id, created_at, updated_at`
+	// Users without a phone store NULL so the unique constraint only applies to actual numbers
+	insertQuery := `INSERT INTO users(name, email, phone, role, password) VALUES($1, $2, NULLIF($3, ''), $4, $5) RETURNING id, created_at, updated_at`
 
 	err := r.db.QueryRowContext(ctx, insertQuery,
 		user.Name,
@@ -53,7 +96,7 @@ func (r *repository) Create(ctx context.Context, user *User) (*User, error) {
 	).Scan(&userID, &createdAt, &updatedAt)
 
 	if err != nil {
-		return nil, err
+		return nil, apperrors.FromDB(err, "user")
 	}
 
 	// Adding db generated values to user
@@ -65,32 +108,26 @@ func (r *repository) Create(ctx context.Context, user *User) (*User, error) {
 }
 
 func (r *repository) GetByEmail(ctx context.Context, email string) (*User, error) {
-	var user User
-	selectQueryByEmail := `SELECT id, name, email, phone, role, password, created_at, updated_at role FROM users WHERE email = $1 AND is_deleted = false`
+	selectQueryByEmail := `SELECT ` + userColumns + ` FROM users WHERE email = $1 AND is_deleted = false`
 
-	err := r.db.QueryRowContext(ctx, selectQueryByEmail, email).Scan(
-		&user.ID,
-		&user.Name,
-		&user.Email,
-		&user.Phone,
-		&user.Role,
-		&user.Password,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	return scanUser(r.db.QueryRowContext(ctx, selectQueryByEmail, email))
+}
 
-	if err != nil {
-		return nil, err
-	}
+func (r *repository) GetByID(ctx context.Context, id int) (*User, error) {
+	selectQueryByID := `SELECT ` + userColumns + ` FROM users WHERE id = $1 AND is_deleted = false`
 
-	return &user, nil
+	return scanUser(r.db.QueryRowContext(ctx, selectQueryByID, id))
 }
 
-func (r *repository) GetByID(ctx context.Context, id int) (*User, error) {
+// userColumns lists the user columns in the order read by scanUser
+const userColumns = `id, name, email, COALESCE(phone, ''), role, password, created_at, updated_at, email_verified_at, phone_verified_at, failed_login_attempts, locked_until, is_deleted, deleted_at, erased_at`
+
+// scanUser reads a user row selected with userColumns
+func scanUser(row rowScanner) (*User, error) {
 	var user User
-	selectQueryByID := `SELECT id, name, email, phone, role, password, created_at, updated_at FROM users WHERE id = $1 AND is_deleted = false`
+	var emailVerifiedAt, phoneVerifiedAt, lockedUntil, deletedAt, erasedAt sql.NullTime
 
-	err := r.db.QueryRowContext(ctx, selectQueryByID, id).Scan(
+	err := row.Scan(
 		&user.ID,
 		&user.Name,
 		&user.Email,
@@ -99,10 +136,33 @@ func (r *repository) GetByID(ctx context.Context, id int) (*User, error) {
 		&user.Password,
 		&user.CreatedAt,
 		&user.UpdatedAt,
+		&emailVerifiedAt,
+		&phoneVerifiedAt,
+		&user.FailedLoginAttempts,
+		&lockedUntil,
+		&user.IsDeleted,
+		&deletedAt,
+		&erasedAt,
 	)
 
 	if err != nil {
-		return nil, err
+		return nil, apperrors.FromDB(err, "user")
+	}
+
+	if emailVerifiedAt.Valid {
+		user.EmailVerifiedAt = &emailVerifiedAt.Time
+	}
+	if phoneVerifiedAt.Valid {
+		user.PhoneVerifiedAt = &phoneVerifiedAt.Time
+	}
+	if lockedUntil.Valid {
+		user.LockedUntil = &lockedUntil.Time
+	}
+	if deletedAt.Valid {
+		user.DeletedAt = &deletedAt.Time
+	}
+	if erasedAt.Valid {
+		user.ErasedAt = &erasedAt.Time
 	}
 
 	return &user, nil
@@ -110,7 +170,8 @@ func (r *repository) GetByID(ctx context.Context, id int) (*User, error) {
 
 func (r *repository) Update(ctx context.Context, user *User) (*User, error) {
 	user.UpdatedAt = time.Now()
-	updateQuery := `UPDATE users SET name = $1, phone = $2, role = $3, updated_at = $4 WHERE id = $5`
+	// A changed phone has to be verified again
+	updateQuery := `UPDATE users SET name = $1, phone = $2, role = $3, updated_at = $4, phone_verified_at = CASE WHEN phone = $2 THEN phone_verified_at END WHERE id = $5`
 
 	_, err := r.db.ExecContext(ctx, updateQuery,
 		user.Name,
@@ -121,6 +182,10 @@ func (r *repository) Update(ctx context.Context, user *User) (*User, error) {
 	)
 
 	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, ErrPhoneTaken
+		}
 		return nil, err
 	}
 
@@ -130,15 +195,166 @@ func (r *repository) Update(ctx context.Context, user *User) (*User, error) {
 func (r *repository) ChangePassword(ctx context.Context, userID int, password string) error {
 	passwordUpdateQuery := `UPDATE users SET password = $1 WHERE id = $2`
 
-	_, err := r.db.ExecContext(ctx, passwordUpdateQuery, password, userID)
+	return r.updateAndRevokeSessions(ctx, userID, passwordUpdateQuery, password, userID)
+}
+
+func (r *repository) Delete(ctx context.Context, userID int) error {
+	// The deletion time starts the grace period before the user data is erased
+	deleteQuery := `UPDATE users SET is_deleted = true, deleted_at = CURRENT_TIMESTAMP WHERE id = $1`
+
+	return r.updateAndRevokeSessions(ctx, userID, deleteQuery, userID)
+}
+
+// updateAndRevokeSessions runs the update along with revoking the user's refresh tokens in one transaction, the
+// access token revocation works at second precision so the refresh tokens are revoked by row instead
+func (r *repository) updateAndRevokeSessions(ctx context.Context, userID int, updateQuery string, args ...any) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	// Rollback is a no-op once the transaction is committed
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, updateQuery, args...); err != nil {
+		return err
+	}
+
+	revokeQuery := `UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND revoked_at IS NULL`
+	if _, err := tx.ExecContext(ctx, revokeQuery, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *repository) MarkEmailVerified(ctx context.Context, userID int, email string) error {
+	verifyQuery := `UPDATE users SET email_verified_at = COALESCE(email_verified_at, CURRENT_TIMESTAMP) WHERE id = $1 AND email = $2`
+
+	_, err := r.db.ExecContext(ctx, verifyQuery, userID, email)
 
 	return err
 }
 
-func (r *repository) Delete(ctx context.Context, userID int) error {
-	deleteQuery := `UPDATE users SET is_deleted = true WHERE id = $1`
+func (r *repository) MarkPhoneVerified(ctx context.Context, userID int, phone string) error {
+	verifyQuery := `UPDATE users SET phone_verified_at = COALESCE(phone_verified_at, CURRENT_TIMESTAMP) WHERE id = $1 AND phone = $2`
 
-	_, err := r.db.ExecContext(ctx, deleteQuery, userID)
+	_, err := r.db.ExecContext(ctx, verifyQuery, userID, phone)
+
+	return err
+}
+
+func (r *repository) IncrementFailedLogins(ctx context.Context, userID int) (int, error) {
+	var failedLoginAttempts int
+	incrementQuery := `UPDATE users SET failed_login_attempts = failed_login_attempts + 1 WHERE id = $1 RETURNING failed_login_attempts`
+
+	err := r.db.QueryRowContext(ctx, incrementQuery, userID).Scan(&failedLoginAttempts)
+
+	return failedLoginAttempts, err
+}
+
+func (r *repository) LockUntil(ctx context.Context, userID int, until time.Time) error {
+	lockQuery := `UPDATE users SET locked_until = $1 WHERE id = $2`
+
+	_, err := r.db.ExecContext(ctx, lockQuery, until, userID)
+
+	return err
+}
+
+func (r *repository) ResetFailedLogins(ctx context.Context, userID int) error {
+	resetQuery := `UPDATE users SET failed_login_attempts = 0, locked_until = NULL WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, resetQuery, userID)
+
+	return err
+}
+
+func (r *repository) GetAll(ctx context.Context, req *ListUserReq, page *pagination.Page) (*[]User, error) {
+	// The search is a substring match, so LIKE wildcards in it are matched literally
+	search := ""
+	if req.Query != "" {
+		search = "%" + likeEscaper.Replace(req.Query) + "%"
+	}
+
+	selectQuery, args := page.Apply(
+		`SELECT `+userColumns+`
+		FROM users
+		WHERE ($1 = '' OR name ILIKE $1 OR email ILIKE $1 OR phone ILIKE $1)
+			AND ($2 = '' OR role = $2)
+			AND (CASE $3 WHEN 'include' THEN true WHEN 'only' THEN is_deleted ELSE NOT is_deleted END)`,
+		[]any{search, req.Role, req.Deleted},
+	)
+
+	rows, err := r.db.QueryContext(ctx, selectQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		users = append(users, *user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &users, nil
+}
+
+// likeEscaper escapes the LIKE wildcards and the default escape character
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (r *repository) GetByIDWithDeleted(ctx context.Context, id int) (*User, error) {
+	selectQueryByID := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
+
+	return scanUser(r.db.QueryRowContext(ctx, selectQueryByID, id))
+}
+
+func (r *repository) Restore(ctx context.Context, userID int) error {
+	restoreQuery := `UPDATE users SET is_deleted = false, deleted_at = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = $1 AND is_deleted = true AND erased_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, restoreQuery, userID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrUserNotDeleted
+	}
+
+	return nil
+}
+
+func (r *repository) UpdateRole(ctx context.Context, userID int, role string) error {
+	roleQuery := `UPDATE users SET role = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
+
+	_, err := r.db.ExecContext(ctx, roleQuery, role, userID)
+
+	return err
+}
+
+func (r *repository) UpdateEmail(ctx context.Context, userID int, email string) error {
+	// The new email was confirmed through the link mailed to it, so it is verified as well
+	updateQuery := `UPDATE users SET email = $1, email_verified_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
+
+	_, err := r.db.ExecContext(ctx, updateQuery, email, userID)
+
+	// Deleted users keep their email until erased, so the unique constraint is the only reliable check
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrEmailTaken
+	}
 
 	return err
 }
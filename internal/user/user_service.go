@@ -2,13 +2,18 @@ package user
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"time"
 
 	"github.com/aslam-ep/go-e-commerce/config"
+	"github.com/aslam-ep/go-e-commerce/internal/pagination"
+	"github.com/aslam-ep/go-e-commerce/internal/revocation"
 	"github.com/aslam-ep/go-e-commerce/utils"
 )
 
+// ListSorts are the sorts of the user list, newest first by default
+var ListSorts = []string{"-" + pagination.SortByCreatedAt, pagination.SortByCreatedAt, "-" + pagination.SortByID, pagination.SortByID}
+
 // Service interface for the user service
 type Service interface {
 	// UpdateUser Updates an existing user's information based on the provided request and returns the updated user's details.
@@ -22,18 +27,32 @@ type Service interface {
 
 	// DeleteUser Deletes a user by their ID and returns a message indicating success or failure.
 	DeleteUser(c context.Context, id int) (*utils.MessageRes, error)
+
+	// ListUsers returns a page of users matching the search and filters, admin only.
+	ListUsers(c context.Context, req *ListUserReq, params pagination.Params) (*ListUserRes, error)
+
+	// GetUserForAdmin retrieves a user's details by their ID, including soft deleted users.
+	GetUserForAdmin(c context.Context, id int) (*User, error)
+
+	// RestoreUser restores a soft deleted user.
+	RestoreUser(c context.Context, id int) (*utils.MessageRes, error)
+
+	// ChangeUserRole changes the role of a user and ends the tokens carrying the old role.
+	ChangeUserRole(c context.Context, req *ChangeRoleReq) (*utils.MessageRes, error)
 }
 
 type service struct {
-	userRepo Repository
-	timeout  time.Duration
+	userRepo        Repository
+	revocationStore revocation.Store
+	timeout         time.Duration
 }
 
 // NewService initialize and return the Service
-func NewService(ur Repository) Service {
+func NewService(ur Repository, rs revocation.Store) Service {
 	return &service{
-		userRepo: ur,
-		timeout:  time.Duration(config.AppConfig.DBTimeout) * time.Second,
+		userRepo:        ur,
+		revocationStore: rs,
+		timeout:         time.Duration(config.AppConfig.DBTimeout) * time.Second,
 	}
 }
 
@@ -54,6 +73,9 @@ func (s *service) GetUserByID(c context.Context, id int) (*User, error) {
 		Role:      user.Role,
 		CreatedAt: user.CreatedAt,
 		UpdatedAt: user.UpdatedAt,
+
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		PhoneVerifiedAt: user.PhoneVerifiedAt,
 	}
 
 	return res, nil
@@ -64,16 +86,31 @@ func (s *service) UpdateUser(c context.Context, req *UpdateUserReq) (*User, erro
 	defer cancel()
 
 	// Check user exist before updating
-	_, err := s.userRepo.GetByID(ctx, int(req.ID))
+	existingUser, err := s.userRepo.GetByID(ctx, int(req.ID))
 	if err != nil {
 		return nil, err
 	}
 
+	// Users switch between user and vendor through the profile, admins keep their role and the email isn't updatable
+	role := existingUser.Role
+	if req.Role != "" && existingUser.Role != RoleAdmin {
+		role = req.Role
+	}
+
 	u := &User{
-		ID:    req.ID,
-		Name:  req.Name,
-		Phone: req.Phone,
-		Role:  req.Role,
+		ID:        req.ID,
+		Name:      req.Name,
+		Email:     existingUser.Email,
+		Phone:     req.Phone,
+		Role:      role,
+		CreatedAt: existingUser.CreatedAt,
+
+		EmailVerifiedAt: existingUser.EmailVerifiedAt,
+	}
+
+	// The verification is kept only for an unchanged phone
+	if req.Phone == existingUser.Phone {
+		u.PhoneVerifiedAt = existingUser.PhoneVerifiedAt
 	}
 
 	updatedUser, err := s.userRepo.Update(ctx, u)
@@ -81,6 +118,14 @@ func (s *service) UpdateUser(c context.Context, req *UpdateUserReq) (*User, erro
 		return nil, err
 	}
 
+	// Access tokens carry the role, so the old ones must not outlive it
+	if updatedUser.Role != existingUser.Role {
+		err = s.revocationStore.RevokeUser(ctx, updatedUser.ID, time.Now())
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	res := &User{
 		ID:        updatedUser.ID,
 		Name:      updatedUser.Name,
@@ -89,6 +134,9 @@ func (s *service) UpdateUser(c context.Context, req *UpdateUserReq) (*User, erro
 		Role:      updatedUser.Role,
 		CreatedAt: updatedUser.CreatedAt,
 		UpdatedAt: updatedUser.UpdatedAt,
+
+		EmailVerifiedAt: updatedUser.EmailVerifiedAt,
+		PhoneVerifiedAt: updatedUser.PhoneVerifiedAt,
 	}
 
 	return res, nil
@@ -106,7 +154,7 @@ func (s *service) ChangeUserPassword(c context.Context, req *ResetPasswordReq) (
 
 	// Check current user db password and given password match
 	if !utils.CheckPasswordHash(req.CurrentPassword, user.Password) {
-		return nil, errors.New("current password doesn't match")
+		return nil, ErrPasswordMismatch
 	}
 
 	hashedPassword, err := utils.HashPassword(req.NewPassword)
@@ -119,6 +167,12 @@ func (s *service) ChangeUserPassword(c context.Context, req *ResetPasswordReq) (
 		return nil, err
 	}
 
+	// Tokens issued with the old password must not outlive it
+	err = s.revocationStore.RevokeUser(ctx, user.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
 	res := &utils.MessageRes{
 		Success: true,
 		Message: "Password updated,",
@@ -142,9 +196,124 @@ func (s *service) DeleteUser(c context.Context, id int) (*utils.MessageRes, erro
 		return nil, err
 	}
 
+	err = s.revocationStore.RevokeUser(ctx, user.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	res := &utils.MessageRes{
+		Success: true,
+		Message: fmt.Sprintf("User Deleted, personal data will be erased after %d days.", config.AppConfig.ErasureGraceDays),
+	}
+
+	return res, nil
+}
+
+func (s *service) ListUsers(c context.Context, req *ListUserReq, params pagination.Params) (*ListUserRes, error) {
+	page, err := pagination.NewPage(params, ListSorts...)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	users, err := s.userRepo.GetAll(ctx, req, page)
+	if err != nil {
+		return nil, err
+	}
+
+	pageUsers, info := pagination.Paginate(*users, page, func(u User) pagination.Key {
+		return pagination.Key{ID: u.ID, CreatedAt: u.CreatedAt}
+	})
+
+	for i := range pageUsers {
+		pageUsers[i].Password = ""
+	}
+
+	res := &ListUserRes{
+		Count:      len(pageUsers),
+		Users:      &pageUsers,
+		NextCursor: info.NextCursor,
+		HasMore:    info.HasMore,
+	}
+
+	return res, nil
+}
+
+func (s *service) GetUserForAdmin(c context.Context, id int) (*User, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	user, err := s.userRepo.GetByIDWithDeleted(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Password = ""
+
+	return user, nil
+}
+
+func (s *service) RestoreUser(c context.Context, id int) (*utils.MessageRes, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	// Check user exist before restoring
+	user, err := s.userRepo.GetByIDWithDeleted(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.ErasedAt != nil {
+		return nil, ErrUserErased
+	}
+
+	err = s.userRepo.Restore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &utils.MessageRes{
+		Success: true,
+		Message: "User restored.",
+	}
+
+	return res, nil
+}
+
+func (s *service) ChangeUserRole(c context.Context, req *ChangeRoleReq) (*utils.MessageRes, error) {
+	ctx, cancel := context.WithTimeout(c, s.timeout)
+	defer cancel()
+
+	// Prevents the last admin from locking everyone out of the admin endpoints
+	if req.ID == req.AdminID {
+		return nil, ErrCannotChangeOwnRole
+	}
+
+	user, err := s.userRepo.GetByID(ctx, int(req.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Role == req.Role {
+		return &utils.MessageRes{Success: true, Message: "Role unchanged."}, nil
+	}
+
+	err = s.userRepo.UpdateRole(ctx, int(user.ID), req.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	// Access tokens carry the role, so the old ones must not outlive it
+	err = s.revocationStore.RevokeUser(ctx, user.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
 	res := &utils.MessageRes{
 		Success: true,
-		Message: "User Deleted.",
+		Message: "Role updated.",
 	}
 
 	return res, nil
@@ -2,10 +2,12 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"strconv"
 	"strings"
 
-	"github.com/aslam-ep/go-e-commerce/config"
+	"github.com/aslam-ep/go-e-commerce/internal/revocation"
 	"github.com/aslam-ep/go-e-commerce/utils"
 )
 
@@ -14,32 +16,70 @@ type contextKey string
 // UserContextKey const to hold the custom type for user context value.
 const UserContextKey = contextKey("user")
 
-// AuthMiddleware middleware for checking the given token is valid one.
-func AuthMiddleware(next http.Handler) http.Handler {
+// RoleContextKey const to hold the custom type for user role context value.
+const RoleContextKey = contextKey("role")
+
+// NewAuthMiddleware creates the middleware for checking the given token is signed by the key set and not revoked.
+func NewAuthMiddleware(keys *utils.KeySet, store revocation.Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return authMiddleware(keys, store, next)
+	}
+}
+
+func authMiddleware(keys *utils.KeySet, store revocation.Store, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Get the token from the authorization header
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
-			utils.WriterErrorResponse(w, http.StatusUnauthorized, "Authorization header is missing")
+			utils.WriterErrorResponse(w, r, http.StatusUnauthorized, "Authorization header is missing")
 			return
 		}
 
 		// Bearer token
 		tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
 		if tokenStr == authHeader {
-			utils.WriterErrorResponse(w, http.StatusUnauthorized, "Invalid token")
+			utils.WriterErrorResponse(w, r, http.StatusUnauthorized, "Invalid token")
 			return
 		}
 
 		// Validate token
-		claims, err := utils.ValidateToken(tokenStr, config.AppConfig.JWTSecret)
+		claims, err := utils.ValidateToken(tokenStr, keys)
 		if err != nil {
-			utils.WriterErrorResponse(w, http.StatusUnauthorized, "Invalid token")
+			utils.WriterErrorResponse(w, r, http.StatusUnauthorized, "Invalid token")
+			return
+		}
+
+		// Only access tokens are accepted, MFA tokens are exchanged through the MFA verification
+		tokenClaims, err := utils.ParseTokenClaims(claims)
+		if err != nil || tokenClaims.Type != utils.TokenTypeAccess {
+			utils.WriterErrorResponse(w, r, http.StatusUnauthorized, "Invalid token")
 			return
 		}
 
-		// Store the user id in context
+		// Reject tokens revoked before their expiry
+		revoked, err := store.IsRevoked(r.Context(), tokenClaims.ID, tokenClaims.UserID, tokenClaims.IssuedAt)
+		if err != nil {
+			utils.WriterErrorResponse(w, r, http.StatusInternalServerError, "Unable to verify token")
+			return
+		}
+		if revoked {
+			utils.WriterErrorResponse(w, r, http.StatusUnauthorized, "Token revoked")
+			return
+		}
+
+		// Store the user id and role in context
 		ctx := context.WithValue(r.Context(), UserContextKey, claims["user_id"])
+		ctx = context.WithValue(ctx, RoleContextKey, tokenClaims.Role)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// GetUserID returns the authenticated user id stored in the context by AuthMiddleware.
+func GetUserID(ctx context.Context) (int, error) {
+	userIDStr, ok := ctx.Value(UserContextKey).(string)
+	if !ok {
+		return -1, errors.New("user not authorized")
+	}
+
+	return strconv.Atoi(userIDStr)
+}
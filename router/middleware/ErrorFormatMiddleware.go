@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+
+	chiMiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"github.com/aslam-ep/go-e-commerce/config"
+	"github.com/aslam-ep/go-e-commerce/utils"
+)
+
+// ErrorFormat middleware to render errors as problem details when the Accept header asks for them,
+// the request id is echoed back so errors can be traced
+func ErrorFormat(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := chiMiddleware.GetReqID(r.Context())
+		if requestID != "" {
+			w.Header().Set(chiMiddleware.RequestIDHeader, requestID)
+		}
+
+		if utils.AcceptsProblem(r) {
+			r = utils.WithProblem(r, config.AppConfig.ProblemTypeBaseURL, r.URL.Path, requestID)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
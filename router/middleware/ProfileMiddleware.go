@@ -13,14 +13,14 @@ func ProfileMiddleware(next http.Handler) http.Handler {
 		// Retrieving user id from context by auth middleware
 		userID, ok := r.Context().Value(UserContextKey).(string)
 		if !ok {
-			utils.WriterErrorResponse(w, http.StatusUnauthorized, "User not authorized")
+			utils.WriterErrorResponse(w, r, http.StatusUnauthorized, "User not authorized")
 			return
 		}
 
 		// Retrieving the user id from url parameter
 		paramID := chi.URLParam(r, "user_id")
 		if userID != paramID {
-			utils.WriterErrorResponse(w, http.StatusUnauthorized, "User not authorized")
+			utils.WriterErrorResponse(w, r, http.StatusUnauthorized, "User not authorized")
 			return
 		}
 
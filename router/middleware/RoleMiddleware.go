@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/aslam-ep/go-e-commerce/utils"
+)
+
+// RequireRole middleware for checking the logged in user has one of the given roles, must run after AuthMiddleware.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Retrieving user role from context by auth middleware
+			role, ok := r.Context().Value(RoleContextKey).(string)
+			if !ok {
+				utils.WriterErrorResponse(w, r, http.StatusForbidden, "User role not permitted")
+				return
+			}
+
+			for _, allowed := range roles {
+				if role == allowed {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			utils.WriterErrorResponse(w, r, http.StatusForbidden, "User role not permitted")
+		})
+	}
+}
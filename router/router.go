@@ -15,6 +15,13 @@ import (
 	_ "github.com/aslam-ep/go-e-commerce/docs/swagger"
 	"github.com/aslam-ep/go-e-commerce/internal/address"
 	"github.com/aslam-ep/go-e-commerce/internal/auth"
+	"github.com/aslam-ep/go-e-commerce/internal/cart"
+	"github.com/aslam-ep/go-e-commerce/internal/health"
+	"github.com/aslam-ep/go-e-commerce/internal/notification"
+	"github.com/aslam-ep/go-e-commerce/internal/order"
+	"github.com/aslam-ep/go-e-commerce/internal/privacy"
+	"github.com/aslam-ep/go-e-commerce/internal/product"
+	"github.com/aslam-ep/go-e-commerce/internal/revocation"
 	"github.com/aslam-ep/go-e-commerce/internal/user"
 	"github.com/aslam-ep/go-e-commerce/router/middleware"
 	"github.com/aslam-ep/go-e-commerce/utils"
@@ -23,28 +30,86 @@ import (
 // Router struct to hold router, database and handlers
 type Router struct {
 	Mux            chi.Router
+	api            chi.Router
 	apiVersion     string
 	authHandler    *auth.Handler
 	userHandler    *user.Handler
 	addressHandler *address.Handler
+	productHandler *product.Handler
+	cartHandler    *cart.Handler
+	orderHandler   *order.Handler
+	privacyHandler *privacy.Handler
+	healthHandler  *health.Handler
+	authMiddleware func(http.Handler) http.Handler
+
+	// ErasureJob erases the data of deleted users after the grace period, run by the caller
+	ErasureJob *privacy.ErasureJob
+
+	// Readiness is flipped by the caller once the server listens and again before it drains
+	Readiness *health.Readiness
+}
+
+// forgotPasswordIPLimit is the number of password reset requests an IP can send per hour, on top of the per email throttle
+const forgotPasswordIPLimit = 20
+
+// limitByIP rate limits the requests per client IP within the window
+func limitByIP(limit int, window time.Duration) func(http.Handler) http.Handler {
+	return httprate.Limit(limit, window,
+		httprate.WithKeyFuncs(httprate.KeyByIP),
+		// Rate limited requests answer in the negotiated error format, Retry-After is already set by httprate
+		httprate.WithLimitHandler(func(w http.ResponseWriter, r *http.Request) {
+			utils.WriterErrorResponse(w, r, http.StatusTooManyRequests, "Too many requests, try again later")
+		}),
+	)
 }
 
 // NewRouter initialize and setup chi router along with the server
-func NewRouter(db *sql.DB) *Router {
-	// Initialize router
+func NewRouter(db *sql.DB, keys *utils.KeySet) (*Router, error) {
+	// Initialize router, the probes are served by the root ahead of the middlewares so polling them is neither
+	// rate limited nor logged
+	root := chi.NewRouter()
 	r := chi.NewRouter()
+	root.Mount("/", r)
+
+	r.Use(chiMiddleware.RequestID)
 	r.Use(chiMiddleware.Logger)
-	r.Use(httprate.LimitByIP(config.AppConfig.APIRateLimit, time.Minute))
+	r.Use(middleware.ErrorFormat)
+	r.Use(limitByIP(config.AppConfig.APIRateLimit, time.Minute))
 	r.Use(middleware.CORS)
 
+	// Unknown routes answer in the negotiated error format as well
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		utils.WriterErrorResponse(w, r, http.StatusNotFound, "Resource not found")
+	})
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		utils.WriterErrorResponse(w, r, http.StatusMethodNotAllowed, "Method not allowed")
+	})
+
+	// Initialize access token revocation
+	revocationStore, err := revocation.NewStore(config.AppConfig.TokenRevocationStore, db)
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize mail delivery
+	mailer, err := notification.NewMailer(config.AppConfig.Mailer, config.AppConfig.MailerFilePath, config.AppConfig.AppEnv)
+	if err != nil {
+		return nil, err
+	}
+	smsSender := notification.NewLogSMSSender()
+
 	// Initialize user domain
 	userRepo := user.NewRepository(db)
-	userServ := user.NewService(userRepo)
+	userServ := user.NewService(userRepo, revocationStore)
 	userHandler := user.NewHandler(userServ)
 
 	// Initialize auth domain
 	authRepo := auth.NewRepository(db)
-	authServ := auth.NewService(userRepo, authRepo)
+	socialProviders, err := auth.NewSocialProviders(config.AppConfig.OIDCProviders)
+	if err != nil {
+		return nil, err
+	}
+	authServ := auth.NewService(userRepo, authRepo, revocationStore, mailer, smsSender, keys, socialProviders)
 	authHandler := auth.NewHandler(authServ)
 
 	// Initialize address domain
@@ -52,18 +117,61 @@ func NewRouter(db *sql.DB) *Router {
 	addressServ := address.NewService(addressRepo)
 	addressHandler := address.NewHandler(addressServ)
 
+	// Initialize product domain
+	productRepo := product.NewRepository(db)
+	productServ := product.NewService(productRepo)
+	productHandler := product.NewHandler(productServ)
+
+	// Initialize cart domain
+	cartRepo := cart.NewRepository(db)
+	cartServ := cart.NewService(cartRepo, productRepo)
+	cartHandler := cart.NewHandler(cartServ)
+
+	// Initialize order domain
+	orderRepo := order.NewRepository(db)
+	orderServ := order.NewService(orderRepo, addressRepo, userRepo)
+	orderHandler := order.NewHandler(orderServ)
+
+	// Initialize privacy domain
+	privacyRepo := privacy.NewRepository(db)
+	privacyServ := privacy.NewService(privacyRepo, userRepo, addressRepo, orderRepo, authServ)
+	privacyHandler := privacy.NewHandler(privacyServ)
+	erasureJob := privacy.NewErasureJob(privacyServ, time.Duration(config.AppConfig.ErasureJobIntervalMinutes)*time.Minute)
+
+	// Initialize health domain
+	readiness := health.NewReadiness()
+	healthRepo := health.NewRepository(db)
+	healthServ := health.NewService(healthRepo, readiness)
+	healthHandler := health.NewHandler(healthServ)
+
 	return &Router{
-		Mux:            r,
+		Mux:            root,
+		api:            r,
 		apiVersion:     "/api/v1",
 		authHandler:    authHandler,
 		userHandler:    userHandler,
 		addressHandler: addressHandler,
-	}
+		productHandler: productHandler,
+		cartHandler:    cartHandler,
+		orderHandler:   orderHandler,
+		privacyHandler: privacyHandler,
+		healthHandler:  healthHandler,
+		authMiddleware: middleware.NewAuthMiddleware(keys, revocationStore),
+		ErasureJob:     erasureJob,
+		Readiness:      readiness,
+	}, nil
 }
 
 // SetupRoutes Initialize end points
 func (router Router) SetupRoutes() {
-	router.Mux.Route(router.apiVersion, func(r chi.Router) {
+	// Public keys are served from the well known location outside the api version
+	router.api.Get("/.well-known/jwks.json", router.authHandler.JWKS)
+
+	// Probes are served outside the api version as well
+	router.Mux.Get("/healthz", router.healthHandler.Liveness)
+	router.Mux.Get("/readyz", router.healthHandler.Readiness)
+
+	router.api.Route(router.apiVersion, func(r chi.Router) {
 		r.Get("/ping", func(w http.ResponseWriter, r *http.Request) {
 			utils.WriteResponse(w, http.StatusAccepted, &utils.MessageRes{
 				Success: true,
@@ -79,15 +187,35 @@ func (router Router) SetupRoutes() {
 			r.Post("/register", router.authHandler.Register)
 			r.Post("/login", router.authHandler.Login)
 			r.Post("/refresh-token", router.authHandler.RefreshToken)
+			r.Post("/logout", router.authHandler.Logout)
+			r.With(limitByIP(forgotPasswordIPLimit, time.Hour)).Post("/forgot-password", router.authHandler.ForgotPassword)
+			r.Post("/reset-password", router.authHandler.ResetPassword)
+			r.Post("/verify/confirm", router.authHandler.ConfirmVerification)
+			r.Post("/verify/resend", router.authHandler.ResendVerification)
+			r.Post("/mfa/verify", router.authHandler.VerifyMFA)
+			r.Post("/email/confirm", router.authHandler.ConfirmEmailChange)
+			r.Get("/oidc/{provider}/login", router.authHandler.OIDCLogin)
+			r.Get("/oidc/{provider}/callback", router.authHandler.OIDCCallback)
+			r.Post("/oidc/exchange", router.authHandler.OIDCExchange)
+
+			r.With(router.authMiddleware).Group(func(r chi.Router) {
+				r.Post("/logout-all", router.authHandler.LogoutAll)
+				r.Get("/sessions", router.authHandler.ListSessions)
+				r.Post("/mfa/enroll", router.authHandler.EnrollMFA)
+				r.Post("/mfa/confirm", router.authHandler.ConfirmMFA)
+				r.Post("/mfa/disable", router.authHandler.DisableMFA)
+				r.Post("/email/change", router.authHandler.ChangeEmail)
+			})
 		})
 
 		// User Router group
-		r.With(middleware.AuthMiddleware, middleware.ProfileMiddleware).
-			Route("users/{user_id}", func(r chi.Router) {
+		r.With(router.authMiddleware, middleware.ProfileMiddleware).
+			Route("/users/{user_id}", func(r chi.Router) {
 				r.Get("/", router.userHandler.GetUser)
 				r.Put("/update", router.userHandler.UpdateUser)
 				r.Put("/reset-password", router.userHandler.ChangePassword)
 				r.Delete("/delete", router.userHandler.DeleteUser)
+				r.Get("/export", router.privacyHandler.ExportUserData)
 
 				// Address Router group
 				r.Route("/addresses", func(r chi.Router) {
@@ -100,6 +228,68 @@ func (router Router) SetupRoutes() {
 						r.Delete("/delete", router.addressHandler.DeleteAddress)
 					})
 				})
+
+				// Cart Router group
+				r.Route("/cart", func(r chi.Router) {
+					r.Get("/", router.cartHandler.GetCart)
+					r.Post("/add", router.cartHandler.AddItem)
+					r.Delete("/clear", router.cartHandler.ClearCart)
+					r.Route("/{item_id}", func(r chi.Router) {
+						r.Put("/update", router.cartHandler.UpdateItemQuantity)
+						r.Delete("/delete", router.cartHandler.RemoveItem)
+					})
+				})
+
+				// Order Router group
+				r.Route("/orders", func(r chi.Router) {
+					r.Get("/", router.orderHandler.ListOrders)
+					r.Post("/checkout", router.orderHandler.Checkout)
+					r.Route("/{order_id}", func(r chi.Router) {
+						r.Get("/", router.orderHandler.GetOrderByID)
+						r.Put("/cancel", router.orderHandler.CancelOrder)
+					})
+				})
+			})
+
+		// Product Router group
+		r.Route("/products", func(r chi.Router) {
+			r.Get("/", router.productHandler.GetAllProducts)
+			r.Get("/{product_id}", router.productHandler.GetProductByID)
+
+			// Vendor only product management
+			r.With(router.authMiddleware, middleware.RequireRole(user.RoleVendor)).Group(func(r chi.Router) {
+				r.Post("/create", router.productHandler.CreateProduct)
+				r.Put("/{product_id}/update", router.productHandler.UpdateProduct)
+				r.Delete("/{product_id}/delete", router.productHandler.DeleteProduct)
 			})
+		})
+
+		// Vendor Router group
+		r.With(router.authMiddleware, middleware.RequireRole(user.RoleVendor)).Route("/vendor", func(r chi.Router) {
+			r.Route("/order-items", func(r chi.Router) {
+				r.Get("/", router.orderHandler.ListVendorOrderItems)
+				r.Put("/{item_id}/pack", router.orderHandler.PackOrderItem)
+				r.Put("/{item_id}/ship", router.orderHandler.ShipOrderItem)
+			})
+			r.Get("/orders/{order_id}/fulfilment", router.orderHandler.GetVendorOrderFulfilment)
+		})
+
+		// Admin Router group
+		r.With(router.authMiddleware, middleware.RequireRole(user.RoleAdmin)).Route("/admin", func(r chi.Router) {
+			r.Put("/orders/{order_id}/status", router.orderHandler.UpdateOrderStatus)
+
+			r.Route("/users", func(r chi.Router) {
+				r.Get("/", router.userHandler.ListUsers)
+
+				r.Route("/{user_id}", func(r chi.Router) {
+					r.Get("/", router.userHandler.GetUserForAdmin)
+					r.Put("/restore", router.userHandler.RestoreUser)
+					r.Put("/role", router.userHandler.ChangeUserRole)
+					r.Put("/lock", router.authHandler.LockUser)
+					r.Put("/unlock", router.authHandler.UnlockUser)
+					r.Put("/logout", router.authHandler.ForceLogout)
+				})
+			})
+		})
 	})
 }
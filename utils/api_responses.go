@@ -1,7 +1,8 @@
 package utils
 
-// MessageRes struct for default response success status and message
+// MessageRes struct for default response success status and message, errors carry a stable code as well
 type MessageRes struct {
 	Success bool   `json:"success"`
 	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
 }
@@ -4,7 +4,11 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
+	"log"
 	"net/http"
+	"strings"
+
+	"github.com/aslam-ep/go-e-commerce/apperrors"
 )
 
 // ReadFromRequest reads the JSON request body and decode it into the provided interface
@@ -34,14 +38,43 @@ func ReadFromRequest(r *http.Request, requestBody any) error {
 	return nil
 }
 
-// WriterErrorResponse writes a Error JSON response with the provided status code and error message
-func WriterErrorResponse(w http.ResponseWriter, status int, message string) {
-	res := &MessageRes{
-		Success: false,
-		Message: message,
+// WriterErrorResponse writes a Error JSON response with the provided status code and error message,
+// the code of the response is derived from the status
+func WriterErrorResponse(w http.ResponseWriter, r *http.Request, status int, message string) {
+	writeErrorResponse(w, r, status, strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_"), message, nil)
+}
+
+// WriteError writes the Error JSON response of a service error, domain errors get the status of their kind
+// along with their code, any other error is logged and hidden behind a generic internal error
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	var appErr *apperrors.Error
+	if !errors.As(err, &appErr) {
+		log.Println("Internal error:", err)
+		WriterErrorResponse(w, r, http.StatusInternalServerError, "internal server error")
+		return
 	}
 
-	WriteResponse(w, status, res)
+	writeErrorResponse(w, r, ErrorStatus(appErr.Kind), appErr.Code, appErr.Message, nil)
+}
+
+// ErrorStatus returns the HTTP status code of the domain error kind.
+func ErrorStatus(kind apperrors.Kind) int {
+	switch kind {
+	case apperrors.KindNotFound:
+		return http.StatusNotFound
+	case apperrors.KindConflict:
+		return http.StatusConflict
+	case apperrors.KindValidation:
+		return http.StatusBadRequest
+	case apperrors.KindUnauthorized:
+		return http.StatusUnauthorized
+	case apperrors.KindForbidden:
+		return http.StatusForbidden
+	case apperrors.KindLimitExceeded:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
 }
 
 // WriteResponse writes a JSON response with the provided status code and data
@@ -8,26 +8,56 @@ import (
 	"github.com/golang-jwt/jwt"
 )
 
-// GenerateToken generates a JWT token for a user with a specified expiration time.
-func GenerateToken(userID int64, secret string, expiry time.Duration) (string, error) {
+// Token types, MFA tokens only prove the password step of a login and can't access the API.
+const (
+	TokenTypeAccess = "access"
+	TokenTypeMFA    = "mfa"
+)
+
+// TokenClaims holds the typed claims of a validated token.
+type TokenClaims struct {
+	ID        string
+	Type      string
+	UserID    int64
+	Role      string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// GenerateToken generates an access token with a unique id for a user and role with a specified expiration time,
+// signed by the current key of the key set.
+func GenerateToken(userID int64, role string, keys *KeySet, expiry time.Duration) (string, error) {
+	return generateToken(TokenTypeAccess, userID, role, keys, expiry)
+}
+
+// GenerateMFAToken generates a token for completing the login of a user with a second factor.
+func GenerateMFAToken(userID int64, keys *KeySet, expiry time.Duration) (string, error) {
+	return generateToken(TokenTypeMFA, userID, "", keys, expiry)
+}
+
+// generateToken generates a signed token of the given type.
+func generateToken(tokenType string, userID int64, role string, keys *KeySet, expiry time.Duration) (string, error) {
+	jti, err := GenerateRandomToken(16)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
 	claims := jwt.MapClaims{
+		"jti":     jti,
+		"typ":     tokenType,
 		"user_id": strconv.Itoa(int(userID)),
-		"exp":     time.Now().Add(expiry).Unix(),
+		"role":    role,
+		"iat":     now.Unix(),
+		"exp":     now.Add(expiry).Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secret))
+	return keys.sign(claims)
 }
 
-// ValidateToken validates a JWT token and returns the claims if valid.
-func ValidateToken(tokenStr, secret string) (jwt.MapClaims, error) {
-	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("unexpected signing method")
-		}
-
-		return []byte(secret), nil
-	})
+// ValidateToken validates a JWT token against the verification keys of the key set and returns the claims if valid.
+func ValidateToken(tokenStr string, keys *KeySet) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenStr, keys.keyFunc)
 	if err != nil {
 		return nil, err
 	}
@@ -39,3 +69,32 @@ func ValidateToken(tokenStr, secret string) (jwt.MapClaims, error) {
 
 	return claims, nil
 }
+
+// ParseTokenClaims converts the validated claims into TokenClaims, tokens without a jti are rejected.
+func ParseTokenClaims(claims jwt.MapClaims) (*TokenClaims, error) {
+	jti, _ := claims["jti"].(string)
+	tokenType, _ := claims["typ"].(string)
+	userIDStr, _ := claims["user_id"].(string)
+	role, _ := claims["role"].(string)
+	iat, _ := claims["iat"].(float64)
+	exp, _ := claims["exp"].(float64)
+
+	userID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if err != nil || jti == "" {
+		return nil, errors.New("invalid token claims")
+	}
+
+	// Tokens issued before the type claim are access tokens
+	if tokenType == "" {
+		tokenType = TokenTypeAccess
+	}
+
+	return &TokenClaims{
+		ID:        jti,
+		Type:      tokenType,
+		UserID:    userID,
+		Role:      role,
+		IssuedAt:  time.Unix(int64(iat), 0),
+		ExpiresAt: time.Unix(int64(exp), 0),
+	}, nil
+}
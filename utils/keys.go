@@ -0,0 +1,259 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// verificationKey holds a public key along with the signing method it verifies.
+type verificationKey struct {
+	method jwt.SigningMethod
+	key    interface{}
+}
+
+// KeySet holds the key used to sign tokens and every key accepted while verifying them.
+type KeySet struct {
+	signingKeyID     string
+	signingMethod    jwt.SigningMethod
+	signingKey       interface{}
+	verificationKeys map[string]verificationKey
+}
+
+// JWK represents a single public key in the JSON Web Key format.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+// JWKS represents the public keys published for verifying tokens.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// NewHMACKeySet creates a key set signing and verifying HS256 tokens with a shared secret.
+func NewHMACKeySet(secret string) *KeySet {
+	key := []byte(secret)
+
+	return &KeySet{
+		signingMethod: jwt.SigningMethodHS256,
+		signingKey:    key,
+		verificationKeys: map[string]verificationKey{
+			"": {method: jwt.SigningMethodHS256, key: key},
+		},
+	}
+}
+
+// LoadKeySet loads the RSA or Ed25519 private key used for signing from a PEM file, along with the public keys
+// still accepted during rotation given as comma separated kid=path pairs.
+func LoadKeySet(signingKeyID, signingKeyFile, verificationKeys string) (*KeySet, error) {
+	if signingKeyID == "" {
+		return nil, errors.New("signing key id is required")
+	}
+
+	pemBytes, err := os.ReadFile(signingKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %v", err)
+	}
+
+	keySet := &KeySet{
+		signingKeyID:     signingKeyID,
+		verificationKeys: make(map[string]verificationKey),
+	}
+
+	if rsaKey, err := jwt.ParseRSAPrivateKeyFromPEM(pemBytes); err == nil {
+		keySet.signingMethod = jwt.SigningMethodRS256
+		keySet.signingKey = rsaKey
+		keySet.verificationKeys[signingKeyID] = verificationKey{method: jwt.SigningMethodRS256, key: &rsaKey.PublicKey}
+	} else if edKey, err := jwt.ParseEdPrivateKeyFromPEM(pemBytes); err == nil {
+		keySet.signingMethod = jwt.SigningMethodEdDSA
+		keySet.signingKey = edKey
+		keySet.verificationKeys[signingKeyID] = verificationKey{
+			method: jwt.SigningMethodEdDSA,
+			key:    edKey.(ed25519.PrivateKey).Public(),
+		}
+	} else {
+		return nil, errors.New("signing key must be an RSA or Ed25519 private key")
+	}
+
+	for _, pair := range strings.Split(verificationKeys, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		kid, path, found := strings.Cut(pair, "=")
+		if !found || kid == "" || path == "" {
+			return nil, fmt.Errorf("invalid verification key %q, expected kid=path", pair)
+		}
+
+		key, err := loadVerificationKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load verification key %s: %v", kid, err)
+		}
+
+		// The signing key always wins so a stale entry cannot shadow it
+		if _, exists := keySet.verificationKeys[kid]; !exists {
+			keySet.verificationKeys[kid] = *key
+		}
+	}
+
+	return keySet, nil
+}
+
+// loadVerificationKey reads an RSA or Ed25519 public key from a PEM file.
+func loadVerificationKey(path string) (*verificationKey, error) {
+	pemBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if rsaKey, err := jwt.ParseRSAPublicKeyFromPEM(pemBytes); err == nil {
+		return &verificationKey{method: jwt.SigningMethodRS256, key: rsaKey}, nil
+	}
+
+	if edKey, err := jwt.ParseEdPublicKeyFromPEM(pemBytes); err == nil {
+		return &verificationKey{method: jwt.SigningMethodEdDSA, key: edKey}, nil
+	}
+
+	return nil, errors.New("key must be an RSA or Ed25519 public key")
+}
+
+// sign signs the token with the current signing key and sets its kid header.
+func (ks *KeySet) sign(claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(ks.signingMethod, claims)
+	if ks.signingKeyID != "" {
+		token.Header["kid"] = ks.signingKeyID
+	}
+
+	return token.SignedString(ks.signingKey)
+}
+
+// keyFunc returns the verification key matching the kid header and signing method of the token.
+func (ks *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok := ks.verificationKeys[kid]
+	if !ok {
+		return nil, errors.New("unknown signing key")
+	}
+
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, errors.New("unexpected signing method")
+	}
+
+	return key.key, nil
+}
+
+// JWKS returns the public verification keys, shared secrets are never published.
+func (ks *KeySet) JWKS() JWKS {
+	jwks := JWKS{Keys: make([]JWK, 0, len(ks.verificationKeys))}
+
+	for kid, key := range ks.verificationKeys {
+		switch publicKey := key.key.(type) {
+		case *rsa.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				KeyType:   "RSA",
+				KeyID:     kid,
+				Use:       "sig",
+				Algorithm: key.method.Alg(),
+				N:         base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+				E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			jwks.Keys = append(jwks.Keys, JWK{
+				KeyType:   "OKP",
+				KeyID:     kid,
+				Use:       "sig",
+				Algorithm: key.method.Alg(),
+				Curve:     "Ed25519",
+				X:         base64.RawURLEncoding.EncodeToString(publicKey),
+			})
+		}
+	}
+
+	sort.Slice(jwks.Keys, func(i, j int) bool {
+		return jwks.Keys[i].KeyID < jwks.Keys[j].KeyID
+	})
+
+	return jwks
+}
+
+// NewKeySetFromJWKS creates a verification only key set from a published JWKS, keys of unsupported types are skipped.
+func NewKeySetFromJWKS(jwks *JWKS) (*KeySet, error) {
+	keySet := &KeySet{
+		verificationKeys: make(map[string]verificationKey),
+	}
+
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		switch jwk.KeyType {
+		case "RSA":
+			n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+			if err != nil {
+				return nil, fmt.Errorf("invalid modulus of key %s: %v", jwk.KeyID, err)
+			}
+			e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+			if err != nil {
+				return nil, fmt.Errorf("invalid exponent of key %s: %v", jwk.KeyID, err)
+			}
+
+			keySet.verificationKeys[jwk.KeyID] = verificationKey{
+				method: jwt.SigningMethodRS256,
+				key: &rsa.PublicKey{
+					N: new(big.Int).SetBytes(n),
+					E: int(new(big.Int).SetBytes(e).Int64()),
+				},
+			}
+		case "OKP":
+			if jwk.Curve != "Ed25519" {
+				continue
+			}
+			x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+			if err != nil || len(x) != ed25519.PublicKeySize {
+				return nil, fmt.Errorf("invalid public key %s", jwk.KeyID)
+			}
+
+			keySet.verificationKeys[jwk.KeyID] = verificationKey{
+				method: jwt.SigningMethodEdDSA,
+				key:    ed25519.PublicKey(x),
+			}
+		}
+	}
+
+	if len(keySet.verificationKeys) == 0 {
+		return nil, errors.New("no supported signing keys in the key set")
+	}
+
+	// Tokens without a kid header can only be matched when there is a single key
+	if len(keySet.verificationKeys) == 1 {
+		for _, key := range keySet.verificationKeys {
+			keySet.verificationKeys[""] = key
+		}
+	}
+
+	return keySet, nil
+}
+
+// HasKey reports whether the key set has a verification key with the kid.
+func (ks *KeySet) HasKey(kid string) bool {
+	_, ok := ks.verificationKeys[kid]
+	return ok
+}
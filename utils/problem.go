@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details documents.
+const ProblemContentType = "application/problem+json"
+
+// ProblemRes struct for the RFC 7807 problem details of an error, code, request id and the failed fields
+// are extension members
+type ProblemRes struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// problemContextKey is the context key of the problem details preference of a request
+type problemContextKey struct{}
+
+// problemOptions holds the members shared by every problem written for a request
+type problemOptions struct {
+	typeBaseURL string
+	instance    string
+	requestID   string
+}
+
+// WithProblem returns the request marked so errors written for it are rendered as problem details, the type
+// of a problem is its code under the base URL, or about:blank without one.
+func WithProblem(r *http.Request, typeBaseURL, instance, requestID string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), problemContextKey{}, &problemOptions{
+		typeBaseURL: strings.TrimSuffix(typeBaseURL, "/"),
+		instance:    instance,
+		requestID:   requestID,
+	}))
+}
+
+// AcceptsProblem reports whether the Accept header of the request asks for problem details.
+func AcceptsProblem(r *http.Request) bool {
+	for _, accepted := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil || mediaType != ProblemContentType {
+			continue
+		}
+
+		// A zero quality value explicitly refuses the media type
+		if q, err := strconv.ParseFloat(params["q"], 64); err == nil && q <= 0 {
+			return false
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// WantsProblem reports whether errors written for the request are rendered as problem details.
+func WantsProblem(r *http.Request) bool {
+	_, ok := r.Context().Value(problemContextKey{}).(*problemOptions)
+	return ok
+}
+
+// NewProblem creates the problem details of an error written for the request.
+func NewProblem(r *http.Request, status int, code, detail string) *ProblemRes {
+	problem := &ProblemRes{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+
+	if options, ok := r.Context().Value(problemContextKey{}).(*problemOptions); ok {
+		if options.typeBaseURL != "" && code != "" {
+			problem.Type = options.typeBaseURL + "/" + code
+		}
+		problem.Instance = options.instance
+		problem.RequestID = options.requestID
+	}
+
+	return problem
+}
+
+// WriteProblem writes a problem details response, the body embeds ProblemRes when it carries more members.
+func WriteProblem(w http.ResponseWriter, status int, responseBody any) {
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(responseBody); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// writeErrorResponse writes the error in the negotiated format, as problem details or as MessageRes by default
+func writeErrorResponse(w http.ResponseWriter, r *http.Request, status int, code, message string, fieldErrors []FieldError) {
+	if WantsProblem(r) {
+		problem := NewProblem(r, status, code, message)
+		problem.Errors = fieldErrors
+		WriteProblem(w, status, problem)
+		return
+	}
+
+	if fieldErrors != nil {
+		WriteResponse(w, status, &ValidationErrorRes{
+			Success: false,
+			Message: message,
+			Code:    code,
+			Errors:  fieldErrors,
+		})
+		return
+	}
+
+	WriteResponse(w, status, &MessageRes{
+		Success: false,
+		Message: message,
+		Code:    code,
+	})
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA1 key of the RFC 6238 test vectors, ASCII "12345678901234567890" base32 encoded
+var rfcSecret = totpEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestTOTPCode(t *testing.T) {
+	// RFC 6238 Appendix B lists 8 digit codes, the last 6 digits are the 6 digit codes of the same step
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, test := range tests {
+		code, err := TOTPCode(rfcSecret, TOTPStep(time.Unix(test.unix, 0)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code != test.code {
+			t.Fatalf("expected %s at %d, got %s", test.code, test.unix, code)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := TOTPStep(now)
+
+	tests := []struct {
+		step  int64
+		valid bool
+	}{
+		{current - 2, false},
+		{current - 1, true},
+		{current, true},
+		{current + 1, true},
+		{current + 2, false},
+	}
+
+	for _, test := range tests {
+		code, err := TOTPCode(rfcSecret, test.step)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		step, ok := ValidateTOTP(rfcSecret, code, now)
+		if ok != test.valid {
+			t.Fatalf("expected the code of step %d to be valid %t", test.step-current, test.valid)
+		}
+		if ok && step != test.step {
+			t.Fatalf("expected step %d, got %d", test.step, step)
+		}
+	}
+
+	if _, ok := ValidateTOTP("not base32!", "287082", now); ok {
+		t.Fatal("expected an invalid secret to be rejected")
+	}
+}
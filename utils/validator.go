@@ -1,10 +1,96 @@
 package utils
 
-import "github.com/go-playground/validator"
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/locales/en"
+	ut "github.com/go-playground/universal-translator"
+	"gopkg.in/go-playground/validator.v9"
+	en_translations "gopkg.in/go-playground/validator.v9/translations/en"
+)
 
 // Validate variable to hold *validator.Validate
 var Validate *validator.Validate
 
+// translator holds the english translator of the validation messages
+var translator ut.Translator
+
+// FieldError describes a single field failing validation, rule and param are the failed tag and its parameter.
+type FieldError struct {
+	Field    string `json:"field"`
+	JSONName string `json:"json_name"`
+	Rule     string `json:"rule"`
+	Param    string `json:"param,omitempty"`
+	Message  string `json:"message"`
+}
+
+// ValidationErrorRes struct for the response of a request failing validation
+type ValidationErrorRes struct {
+	Success bool         `json:"success"`
+	Message string       `json:"message"`
+	Code    string       `json:"code"`
+	Errors  []FieldError `json:"errors"`
+}
+
 func init() {
 	Validate = validator.New()
+
+	// Report fields by their JSON names so the errors match the request body
+	Validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	english := en.New()
+	translator, _ = ut.New(english, english).GetTranslator("en")
+
+	if err := en_translations.RegisterDefaultTranslations(Validate, translator); err != nil {
+		panic(err)
+	}
+}
+
+// FieldErrors returns the failed fields of a validation error, nil when it has none.
+func FieldErrors(err error) []FieldError {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return nil
+	}
+
+	fieldErrors := make([]FieldError, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		fieldErrors = append(fieldErrors, FieldError{
+			Field:    trimNamespace(fe.StructNamespace()),
+			JSONName: trimNamespace(fe.Namespace()),
+			Rule:     fe.Tag(),
+			Param:    fe.Param(),
+			Message:  fe.Translate(translator),
+		})
+	}
+
+	return fieldErrors
+}
+
+// trimNamespace drops the struct name leading the namespace of a field
+func trimNamespace(namespace string) string {
+	if _, field, found := strings.Cut(namespace, "."); found {
+		return field
+	}
+	return namespace
+}
+
+// WriteValidationError writes the Error JSON response of a failed validation listing every failed field.
+func WriteValidationError(w http.ResponseWriter, r *http.Request, err error) {
+	fieldErrors := FieldErrors(err)
+	if fieldErrors == nil {
+		WriterErrorResponse(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeErrorResponse(w, r, http.StatusBadRequest, "validation_failed", fieldErrors[0].Message, fieldErrors)
 }